@@ -0,0 +1,140 @@
+package gigotest
+
+import (
+	"fmt"
+	"strings"
+)
+
+// context is the number of unchanged lines around the changes of a hunk.
+const context = 3
+
+type edit struct {
+	op   byte // ' ', '-' or '+'
+	line string
+}
+
+// Diff returns the unified diff from a to b, or an empty string if they are equal.
+func Diff(aName, a, bName, b string) string {
+	if a == b {
+		return ""
+	}
+	edits := diffLines(splitLines(a), splitLines(b))
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "--- %v\n+++ %v\n", aName, bName)
+	for start := 0; start < len(edits); {
+		// find the next change.
+		for start < len(edits) && edits[start].op == ' ' {
+			start++
+		}
+		if start == len(edits) {
+			break
+		}
+		from := start - context
+		if from < 0 {
+			from = 0
+		}
+		// extend the hunk while the changes are close enough.
+		end, same := start, 0
+		for end < len(edits) && same <= 2*context {
+			if edits[end].op == ' ' {
+				same++
+			} else {
+				same = 0
+			}
+			end++
+		}
+		end -= same
+		if same > context {
+			end += context
+		} else {
+			end += same
+		}
+		writeHunk(&buf, edits, from, end)
+		start = end
+	}
+	return buf.String()
+}
+
+func writeHunk(buf *strings.Builder, edits []edit, from, to int) {
+	aStart, bStart := 1, 1
+	for _, e := range edits[:from] {
+		if e.op != '+' {
+			aStart++
+		}
+		if e.op != '-' {
+			bStart++
+		}
+	}
+	aLen, bLen := 0, 0
+	for _, e := range edits[from:to] {
+		if e.op != '+' {
+			aLen++
+		}
+		if e.op != '-' {
+			bLen++
+		}
+	}
+	fmt.Fprintf(buf, "@@ -%v,%v +%v,%v @@\n", aStart, aLen, bStart, bLen)
+	for _, e := range edits[from:to] {
+		fmt.Fprintf(buf, "%c%v\n", e.op, e.line)
+	}
+}
+
+// noEOL marks the last line of a source that does not end with a new line,
+// it is printed after the line, as diff does.
+const noEOL = "\n\\ No newline at end of file"
+
+// splitLines returns the lines of s,
+// the last line is marked with noEOL if s does not end with a new line.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	ret := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	if !strings.HasSuffix(s, "\n") {
+		ret[len(ret)-1] += noEOL
+	}
+	return ret
+}
+
+// diffLines returns the edits from a to b, from their longest common subsequence.
+func diffLines(a, b []string) []edit {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	ret := []edit{}
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if a[i] == b[j] {
+			ret = append(ret, edit{' ', a[i]})
+			i++
+			j++
+		} else if lcs[i+1][j] >= lcs[i][j+1] {
+			ret = append(ret, edit{'-', a[i]})
+			i++
+		} else {
+			ret = append(ret, edit{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ret = append(ret, edit{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		ret = append(ret, edit{'+', b[j]})
+	}
+	return ret
+}
@@ -0,0 +1,61 @@
+package gigotest
+
+import "testing"
+
+func TestDiff(t *testing.T) {
+	if d := Diff("a", "x\ny\n", "b", "x\ny\n"); d != "" {
+		t.Errorf("unexpected diff of equal strings %q", d)
+	}
+
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n"
+	b := "1\n2\n3\n4\nfive\n6\n7\n8\n9\n"
+	want := `--- a
++++ b
+@@ -2,7 +2,7 @@
+ 2
+ 3
+ 4
+-5
++five
+ 6
+ 7
+ 8
+`
+	if got := Diff("a", a, "b", b); got != want {
+		t.Errorf("unexpected diff\nwanted=\n%v\ngot=\n%v", want, got)
+	}
+
+	want = `--- a
++++ b
+@@ -1,2 +1,3 @@
+ x
++z
+ y
+`
+	if got := Diff("a", "x\ny\n", "b", "x\nz\ny\n"); got != want {
+		t.Errorf("unexpected diff\nwanted=\n%v\ngot=\n%v", want, got)
+	}
+	// only the final new line differs.
+	want = `--- a
++++ b
+@@ -1,2 +1,2 @@
+ x
+-y
++y
+\ No newline at end of file
+`
+	if got := Diff("a", "x\ny\n", "b", "x\ny"); got != want {
+		t.Errorf("unexpected diff\nwanted=\n%v\ngot=\n%v", want, got)
+	}
+}
+
+func TestNewCase(t *testing.T) {
+	c := NewCase("testdata/x.gigo.go")
+	if c.Golden != "testdata/x.golden" || c.Format {
+		t.Errorf("unexpected case %#v", c)
+	}
+	c = NewCase("examples/x/x.gigo")
+	if c.Golden != "examples/x/x.go" || !c.Format {
+		t.Errorf("unexpected case %#v", c)
+	}
+}
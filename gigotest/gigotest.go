@@ -0,0 +1,116 @@
+// Package gigotest checks the results of gigo files against golden files.
+//
+// The golden file of x.gigo.go is x.golden, the raw result of gen.
+// The golden file of x.gigo is x.go, the result of gen formatted with go/format,
+// so it can be compiled and tested along the gigo file.
+package gigotest
+
+import (
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	program "github.com/mh-cbon/gigo/program/glang"
+)
+
+// Case is a gigo file and the golden file of its result.
+type Case struct {
+	Input   string
+	Golden  string
+	Format  bool            // format the result with go/format.
+	Backend program.Backend // the way the templates are emitted, program.Expand if empty.
+}
+
+// NewCase creates the Case of the gigo file input.
+func NewCase(input string) *Case {
+	if strings.HasSuffix(input, ".gigo") {
+		return &Case{Input: input, Golden: strings.TrimSuffix(input, ".gigo") + ".go", Format: true}
+	}
+	return &Case{Input: input, Golden: strings.TrimSuffix(input, ".gigo.go") + ".golden"}
+}
+
+// Glob returns the cases of the gigo files matching pattern.
+func Glob(pattern string) ([]*Case, error) {
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	ret := []*Case{}
+	for _, f := range files {
+		ret = append(ret, NewCase(f))
+	}
+	return ret, nil
+}
+
+// Generate runs the gigo file through the interpreter and the templates.
+func (c *Case) Generate() ([]byte, error) {
+	fileDef, err := program.InterpretFile(c.Input)
+	if err != nil {
+		return nil, err
+	}
+	instances := program.NewInstances()
+	if c.Backend != "" {
+		instances.Backend = c.Backend
+	}
+	res, err := program.MutateWith(fileDef, instances)
+	if err != nil {
+		return nil, err
+	}
+	ret := []byte(res.String())
+	if c.Format {
+		return format.Source(ret)
+	}
+	return ret, nil
+}
+
+// Check returns an error with a unified diff if the result differs from the golden file.
+func (c *Case) Check() error {
+	got, err := c.Generate()
+	if err != nil {
+		return err
+	}
+	want, err := ioutil.ReadFile(c.Golden)
+	if err != nil {
+		return err
+	}
+	if d := Diff(c.Golden, string(want), c.Input, string(got)); d != "" {
+		return fmt.Errorf("the result differs from %v, run gen -update to update it\n%v", c.Golden, d)
+	}
+	return nil
+}
+
+// Update writes the result to the golden file.
+func (c *Case) Update() error {
+	got, err := c.Generate()
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(c.Golden, got, 0644)
+}
+
+// Run checks every case in a sub test named after its input.
+func Run(t *testing.T, cases []*Case) {
+	if len(cases) == 0 {
+		t.Fatal("no gigo files to check")
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(filepath.ToSlash(c.Input), func(t *testing.T) {
+			if err := c.Check(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// RunGlob checks the cases of the gigo files matching pattern, see Run.
+func RunGlob(t *testing.T, pattern string) {
+	cases, err := Glob(pattern)
+	if err != nil {
+		t.Fatal(err)
+	}
+	Run(t, cases)
+}
@@ -0,0 +1,78 @@
+package generic
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Cloneable is a Tokener that can copy itself with a Cloner.
+type Cloneable interface {
+	CloneWith(c *Cloner) Tokener
+}
+
+// Cloner deep copies expression trees.
+// A node referenced by several fields, such as FuncDecl.Name that is also
+// one of the FuncDecl tokens, is copied once so the copy keeps the same sharing.
+type Cloner struct {
+	copies map[Tokener]Tokener
+}
+
+// NewCloner creates a new Cloner.
+func NewCloner() *Cloner {
+	return &Cloner{copies: map[Tokener]Tokener{}}
+}
+
+// Clone returns the copy of t, t is copied only once.
+// It returns nil if t is nil.
+func (c *Cloner) Clone(t Tokener) Tokener {
+	if t == nil {
+		return nil
+	}
+	if v := reflect.ValueOf(t); v.Kind() == reflect.Ptr && v.IsNil() {
+		return nil
+	}
+	if x, ok := c.copies[t]; ok {
+		return x
+	}
+	x, ok := t.(Cloneable)
+	if !ok {
+		panic(fmt.Sprintf("%T can not be cloned", t))
+	}
+	return x.CloneWith(c)
+}
+
+// Register records that n is the copy of t,
+// CloneWith implementations call it before they copy their children.
+func (c *Cloner) Register(t, n Tokener) {
+	c.copies[t] = n
+}
+
+// Tokens copies a list of tokens.
+func (c *Cloner) Tokens(tokens []Tokener) []Tokener {
+	if tokens == nil {
+		return nil
+	}
+	ret := make([]Tokener, len(tokens))
+	for i, t := range tokens {
+		ret[i] = c.Clone(t)
+	}
+	return ret
+}
+
+// Expression copies the tokens of an Expression.
+func (c *Cloner) Expression(e Expression) Expression {
+	return Expression{Tokens: c.Tokens(e.Tokens)}
+}
+
+// CloneWith implements Cloneable.
+func (f *TokenWithPos) CloneWith(c *Cloner) Tokener {
+	ret := f.Clone()
+	c.Register(f, ret)
+	return ret
+}
+
+// Clone returns a copy of the token.
+func (f *TokenWithPos) Clone() *TokenWithPos {
+	ret := *f
+	return &ret
+}
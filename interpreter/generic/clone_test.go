@@ -0,0 +1,47 @@
+package generic
+
+import (
+	"testing"
+
+	genericlexer "github.com/mh-cbon/gigo/lexer/generic"
+	lexer "github.com/mh-cbon/state-lexer"
+)
+
+func TestClone(t *testing.T) {
+	_, inner, leaf := makeWalkTree()
+
+	tok := leaf.Clone()
+	tok.SetValue("z")
+	if leaf.GetValue() != "c" {
+		t.Errorf("unexpected value wanted=%q, got=%q", "c", leaf.GetValue())
+	}
+	if tok.GetPos() != leaf.GetPos() {
+		t.Errorf("unexpected pos wanted=%v, got=%v", leaf.GetPos(), tok.GetPos())
+	}
+
+	e := NewCloner().Expression(*inner)
+	if e.String() != inner.String() {
+		t.Errorf("unexpected clone wanted=%q, got=%q", inner.String(), e.String())
+	}
+	e.SetTokenValue(genericlexer.WordToken, "x")
+	if inner.String() != "bc" {
+		t.Errorf("unexpected original wanted=%q, got=%q", "bc", inner.String())
+	}
+}
+
+func TestClonerClonesOnce(t *testing.T) {
+	tok := NewTokenWithPos(lexer.Token{Type: genericlexer.WordToken, Value: "a"}, 1, 0)
+	c := NewCloner()
+	a := c.Clone(tok)
+	b := c.Clone(tok)
+	if a != b {
+		t.Errorf("unexpected copies %p != %p", a, b)
+	}
+	if a == Tokener(tok) {
+		t.Errorf("unexpected copy, got the original")
+	}
+	var nilTok *TokenWithPos
+	if c.Clone(nilTok) != nil {
+		t.Errorf("unexpected copy of nil")
+	}
+}
@@ -1,7 +1,10 @@
 package generic
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
 	"strings"
 
 	genericlexer "github.com/mh-cbon/gigo/lexer/generic"
@@ -73,3 +76,84 @@ func DumpTokensWithNamer(tokens []Tokener, typer TokenTyper) {
 	}
 	fmt.Printf("%v%-6v %-20T tokens(%v)\n", x, "end", "<noname>", len(tokens))
 }
+
+// DumpNode is a node of an expression tree as exported by DumpJSON and DumpDot.
+type DumpNode struct {
+	Kind      string      `json:"kind"` // Token, or the type of the node such as FuncDecl.
+	Span      Span        `json:"span"`
+	Type      string      `json:"type,omitempty"` // name of the token type.
+	Value     string      `json:"value,omitempty"`
+	Generated bool        `json:"generated,omitempty"`
+	Children  []*DumpNode `json:"children,omitempty"`
+}
+
+// NewDumpNode exports src, typer gives the names of the token types.
+func NewDumpNode(src Expressioner, typer TokenTyper) *DumpNode {
+	ret := &DumpNode{Kind: nodeKind(src)}
+	if t, ok := src.(Tokener); ok {
+		ret.Span = t.GetSpan()
+	}
+	if t, ok := src.(*TokenWithPos); ok {
+		ret.Type = typer(t.GetType())
+		ret.Value = t.GetValue()
+		ret.Generated = t.Generated
+		return ret
+	}
+	for _, e := range src.GetExprs() {
+		ret.Children = append(ret.Children, NewDumpNode(e, typer))
+	}
+	return ret
+}
+
+func nodeKind(src Expressioner) string {
+	if _, ok := src.(*TokenWithPos); ok {
+		return "Token"
+	}
+	T := fmt.Sprintf("%T", src)
+	return T[strings.LastIndex(T, ".")+1:]
+}
+
+// DumpJSON writes the tree of src as indented json.
+func DumpJSON(w io.Writer, src Expressioner, typer TokenTyper) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(NewDumpNode(src, typer))
+}
+
+// DumpDot writes the tree of src as a graphviz digraph,
+// tokens are the leaves of the graph.
+func DumpDot(w io.Writer, src Expressioner, typer TokenTyper) error {
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, "digraph gigo {")
+	fmt.Fprintln(&buf, "  node [shape=box, fontname=\"monospace\"];")
+	id := 0
+	var write func(n *DumpNode) int
+	write = func(n *DumpNode) int {
+		nid := id
+		id++
+		label := n.Kind
+		shape := ""
+		if n.Kind == "Token" {
+			label = fmt.Sprintf("%v\n%q", n.Type, n.Value)
+			shape = ", shape=plaintext"
+		}
+		if n.Span.IsValid() {
+			label += fmt.Sprintf("\n%v-%v", n.Span.Start, n.Span.End)
+		}
+		fmt.Fprintf(&buf, "  n%v [label=\"%v\"%v];\n", nid, dotEscape(label), shape)
+		for _, c := range n.Children {
+			fmt.Fprintf(&buf, "  n%v -> n%v;\n", nid, write(c))
+		}
+		return nid
+	}
+	write(NewDumpNode(src, typer))
+	fmt.Fprintln(&buf, "}")
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+func dotEscape(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	s = strings.Replace(s, `"`, `\"`, -1)
+	return strings.Replace(s, "\n", `\n`, -1)
+}
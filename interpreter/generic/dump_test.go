@@ -0,0 +1,56 @@
+package generic
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	genericlexer "github.com/mh-cbon/gigo/lexer/generic"
+	lexer "github.com/mh-cbon/state-lexer"
+)
+
+func TestDumpJSON(t *testing.T) {
+	root, _, _ := makeWalkTree()
+
+	var buf bytes.Buffer
+	if err := DumpJSON(&buf, root, genericlexer.TokenType); err != nil {
+		t.Fatal(err)
+	}
+	var got DumpNode
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Kind != "Expression" || len(got.Children) != 3 {
+		t.Fatalf("unexpected root wanted=%q with 3 children, got=%q with %v", "Expression", got.Kind, len(got.Children))
+	}
+	leaf := got.Children[1].Children[1]
+	if leaf.Kind != "Token" || leaf.Type != "WordToken" || leaf.Value != "c" {
+		t.Errorf("unexpected leaf wanted=%q, got=%q %q %q", "Token WordToken c", leaf.Kind, leaf.Type, leaf.Value)
+	}
+	if leaf.Span.Start.Line != 1 {
+		t.Errorf("unexpected leaf line wanted=%v, got=%v", 1, leaf.Span.Start.Line)
+	}
+}
+
+func TestDumpDot(t *testing.T) {
+	root, _, _ := makeWalkTree()
+	root.AddExpr(NewTokenWithPos(lexer.Token{Type: genericlexer.TextToken, Value: `"q"`}, 1, 3))
+
+	var buf bytes.Buffer
+	if err := DumpDot(&buf, root, genericlexer.TokenType); err != nil {
+		t.Fatal(err)
+	}
+	got := buf.String()
+	for _, want := range []string{
+		"digraph gigo {\n",
+		"  n0 [label=\"Expression\\n1:1-1:7\"];\n",
+		"  n0 -> n1;\n",
+		"  n2 -> n3;\n",
+		`  n6 [label="TextToken\n\"\\\"q\\\"\"\n1:4-1:7", shape=plaintext];` + "\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("unexpected dot output, wanted to contain=%q\ngot=%q", want, got)
+		}
+	}
+}
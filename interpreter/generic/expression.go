@@ -33,7 +33,10 @@ func (t TokenPos) String() string {
 // TokenWithPos is a token with a pos.
 type TokenWithPos struct {
 	lexer.Token
-	Pos TokenPos
+	Pos  TokenPos
+	Span Span
+	// Generated is true for a token that does not exist in the source.
+	Generated bool
 }
 
 //SetValue implements Tokener.
@@ -108,12 +111,14 @@ func (f *TokenWithPos) FindAll(T lexer.TokenType) []Expressioner {
 
 // NewTokenWithPos creates a positionned Tokener of token.
 func NewTokenWithPos(t lexer.Token, line, pos int) *TokenWithPos {
+	start := Position{Line: line, Col: pos}
 	return &TokenWithPos{
 		Token: t,
 		Pos: TokenPos{
 			Pos:  pos,
 			Line: line,
 		},
+		Span: Span{Start: start, End: start.Advance(t.Value)},
 	}
 }
 
@@ -130,6 +135,7 @@ type Tokener interface {
 	SetType(lexer.TokenType)
 	String() string
 	GetPos() TokenPos
+	GetSpan() Span
 }
 
 // Expressioner is an interface that defines methods to manipulate a token of tokens.
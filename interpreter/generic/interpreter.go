@@ -252,7 +252,6 @@ func (I *Interpreter) ReadBlock(open lexer.TokenType, close lexer.TokenType) []T
 			I.Next()
 		}
 	}
-	I.Read(close)
 	return I.Current()
 }
 
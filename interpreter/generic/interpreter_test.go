@@ -619,6 +619,26 @@ func TestReadBlock(t *testing.T) {
 	compareTokensLen(t, "ReadBlock", 0, 7, tokens)
 }
 
+func TestReadBlockStopsAtClose(t *testing.T) {
+
+	str := `f((a)) b`
+	d := stringTokenizer(str)
+	interpret := NewInterpreter(d)
+
+	interpret.Read(genericlexer.WordToken)
+	interpret.Read(glanglexer.ParenOpenToken)
+	interpret.Flush()
+
+	// the block (a) ends at its close token, the close token of f( is not read.
+	tokens := interpret.ReadBlock(glanglexer.ParenOpenToken, glanglexer.ParenCloseToken)
+	compareTokensLen(t, "ReadBlock", 0, 3, tokens)
+
+	next := interpret.Next()
+	if next == nil || next.GetType() != glanglexer.ParenCloseToken {
+		t.Errorf("Wrong token after ReadBlock want=%v, got=%v", glanglexer.ParenCloseToken, next)
+	}
+}
+
 func compareTokensLen(t *testing.T, r string, c int, want int, got []Tokener) bool {
 	return compareLen(t, r+" tokens", c, want, len(got))
 }
@@ -3,7 +3,6 @@ package generic
 import (
 	"fmt"
 	"io"
-	"strings"
 	"text/tabwriter"
 
 	lexer "github.com/mh-cbon/state-lexer"
@@ -22,20 +21,18 @@ type TokenerReader interface {
 // ReadTokenWithPos reads lexer.Token, outputs Tokener
 type ReadTokenWithPos struct {
 	Reader TokenReader
-	line   int
-	pos    int
+	File   string
+	pos    Position
 }
 
 // NextToken advance to the next token.
 // returns nil on eof.
 func (r *ReadTokenWithPos) NextToken() Tokener {
 	if next := r.Reader.NextToken(); next != nil {
-		tok := NewTokenWithPos(*next, r.line, r.pos)
-		if k := strings.Count(tok.Value, "\n"); k > 0 {
-			r.line += k
-			r.pos = -1
-		}
-		r.pos += len(tok.Value)
+		tok := NewTokenWithPos(*next, r.pos.Line, r.pos.Col)
+		end := r.pos.Advance(tok.Value)
+		tok.Span = Span{File: r.File, Start: r.pos, End: end}
+		r.pos = end
 		return tok
 	}
 	return nil
@@ -45,11 +42,18 @@ func (r *ReadTokenWithPos) NextToken() Tokener {
 func NewReadTokenWithPos(r TokenReader) *ReadTokenWithPos {
 	return &ReadTokenWithPos{
 		Reader: r,
-		line:   1,
-		pos:    0,
+		pos:    Position{Line: 1},
 	}
 }
 
+// NewReadFileTokenWithPos is like NewReadTokenWithPos,
+// the spans of the tokens refer to file.
+func NewReadFileTokenWithPos(r TokenReader, file string) *ReadTokenWithPos {
+	ret := NewReadTokenWithPos(r)
+	ret.File = file
+	return ret
+}
+
 // ReadNPrettyPrint is a TokenerReader that pretty prints what it reads.
 type ReadNPrettyPrint struct {
 	Reader TokenerReader
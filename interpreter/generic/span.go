@@ -0,0 +1,110 @@
+package generic
+
+import (
+	"fmt"
+
+	lexer "github.com/mh-cbon/state-lexer"
+)
+
+// Position is a location in a source.
+type Position struct {
+	Line   int `json:"line"`   // starts at 1.
+	Col    int `json:"col"`    // starts at 0, it counts runes, not bytes.
+	Offset int `json:"offset"` // bytes from the beginning of the source.
+}
+
+// Advance returns the position after s.
+func (p Position) Advance(s string) Position {
+	for _, r := range s {
+		if r == '\n' {
+			p.Line++
+			p.Col = 0
+		} else {
+			p.Col++
+		}
+	}
+	p.Offset += len(s)
+	return p
+}
+
+// String returns line:col, the column is printed from 1, as go/token does.
+func (p Position) String() string {
+	return fmt.Sprintf("%v:%v", p.Line, p.Col+1)
+}
+
+// Span is the range of source of a node,
+// End is the position right after its last rune.
+type Span struct {
+	File  string   `json:"file,omitempty"`
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+// IsValid returns true if the span was read from a source.
+func (s Span) IsValid() bool {
+	return s.Start.Line > 0
+}
+
+// Contains returns true if p is within the span.
+func (s Span) Contains(p Position) bool {
+	if p.Line < s.Start.Line || p.Line > s.End.Line {
+		return false
+	}
+	if p.Line == s.Start.Line && p.Col < s.Start.Col {
+		return false
+	}
+	if p.Line == s.End.Line && p.Col >= s.End.Col {
+		return false
+	}
+	return true
+}
+
+// Join returns the span from the start of s to the end of o.
+func (s Span) Join(o Span) Span {
+	if !s.IsValid() {
+		return o
+	}
+	if !o.IsValid() {
+		return s
+	}
+	if s.File == "" {
+		s.File = o.File
+	}
+	s.End = o.End
+	return s
+}
+
+func (s Span) String() string {
+	ret := fmt.Sprintf("%v-%v", s.Start, s.End)
+	if s.File != "" {
+		ret = s.File + ":" + ret
+	}
+	return ret
+}
+
+// GetSpan implements Tokener.
+func (f *TokenWithPos) GetSpan() Span {
+	return f.Span
+}
+
+// GetSpan returns the span from the first to the last token read from a source,
+// the generated tokens are skipped.
+func (f *Expression) GetSpan() Span {
+	var ret Span
+	for _, t := range f.Tokens {
+		if x, ok := t.(*TokenWithPos); ok && x.Generated {
+			continue
+		}
+		ret = ret.Join(t.GetSpan())
+	}
+	return ret
+}
+
+// NewGeneratedToken creates a token that does not exist in the source,
+// it is tagged with the span of the node it was generated from.
+func NewGeneratedToken(t lexer.Token, origin Span) *TokenWithPos {
+	ret := NewTokenWithPos(t, origin.Start.Line, origin.Start.Col)
+	ret.Span = origin
+	ret.Generated = true
+	return ret
+}
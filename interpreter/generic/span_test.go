@@ -0,0 +1,74 @@
+package generic
+
+import (
+	"testing"
+
+	genericlexer "github.com/mh-cbon/gigo/lexer/generic"
+	lexer "github.com/mh-cbon/state-lexer"
+)
+
+func TestReadTokenWithSpan(t *testing.T) {
+
+	str := `s := "héhé" /* a
+ébc */ x
+`
+	d := stringTokenizer(str)
+
+	wanted := []Span{
+		{Start: Position{1, 0, 0}, End: Position{1, 1, 1}},     // s
+		{Start: Position{1, 1, 1}, End: Position{1, 2, 2}},     // ws
+		{Start: Position{1, 2, 2}, End: Position{1, 4, 4}},     // :=
+		{Start: Position{1, 4, 4}, End: Position{1, 5, 5}},     // ws
+		{Start: Position{1, 5, 5}, End: Position{1, 11, 13}},   // "héhé"
+		{Start: Position{1, 11, 13}, End: Position{1, 12, 14}}, // ws
+		{Start: Position{1, 12, 14}, End: Position{2, 6, 26}},  // /* a\nébc */
+		{Start: Position{2, 6, 26}, End: Position{2, 7, 27}},   // ws
+		{Start: Position{2, 7, 27}, End: Position{2, 8, 28}},   // x
+	}
+	for i, want := range wanted {
+		tok := d.NextToken()
+		if tok == nil {
+			t.Fatalf("unexpected EOF at token %v", i)
+		}
+		if got := tok.GetSpan(); got != want {
+			t.Errorf("unexpected span of %q wanted=%v, got=%v", tok.GetValue(), want, got)
+		}
+		if got := tok.GetPos(); got.Line != want.Start.Line || got.Pos != want.Start.Col {
+			t.Errorf("unexpected pos of %q wanted=%v, got=%v", tok.GetValue(), want.Start, got)
+		}
+	}
+}
+
+func TestExpressionSpan(t *testing.T) {
+	e := &Expression{}
+	if e.GetSpan().IsValid() {
+		t.Errorf("unexpected valid span of an empty expression")
+	}
+
+	a := NewTokenWithPos(lexer.Token{Type: genericlexer.WordToken, Value: "ab"}, 1, 2)
+	b := NewTokenWithPos(lexer.Token{Type: genericlexer.WordToken, Value: "c"}, 2, 0)
+	// a generated token has the span of its origin, it is not part of the expression.
+	gen := NewGeneratedToken(lexer.Token{Type: genericlexer.WsToken, Value: " "}, Span{Start: Position{Line: 5}, End: Position{Line: 5, Col: 3}})
+	e.AddExpr(gen)
+	e.AddExpr(a)
+	e.AddExpr(gen)
+	e.AddExpr(b)
+
+	want := Span{Start: Position{Line: 1, Col: 2}, End: Position{Line: 2, Col: 1}}
+	// offsets are unknown for tokens that were not read from a source.
+	if got := e.GetSpan(); got.String() != want.String() {
+		t.Errorf("unexpected span wanted=%v, got=%v", want, got)
+	}
+	if got := want.String(); got != "1:3-2:2" {
+		t.Errorf("unexpected span string wanted=%q, got=%q", "1:3-2:2", got)
+	}
+	if !gen.Generated || gen.GetSpan().Start.Line != 5 {
+		t.Errorf("unexpected generated token span wanted=%v, got=%v", "5:1-5:4", gen.GetSpan())
+	}
+	if !want.Contains(Position{Line: 1, Col: 5}) {
+		t.Errorf("unexpected span %v does not contain 1:5", want)
+	}
+	if want.Contains(Position{Line: 2, Col: 1}) || want.Contains(Position{Line: 1, Col: 1}) {
+		t.Errorf("unexpected span %v contains 2:1 or 1:1", want)
+	}
+}
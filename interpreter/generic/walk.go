@@ -0,0 +1,97 @@
+package generic
+
+// Visitor visits the nodes of an expression tree.
+// If Visit returns a non nil Visitor w,
+// Walk visits each child of node with w, then calls w.Visit(nil).
+type Visitor interface {
+	Visit(node Expressioner) (w Visitor)
+}
+
+// Walk traverses an expression tree in depth-first order,
+// it starts by calling v.Visit(node).
+func Walk(v Visitor, node Expressioner) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+	for _, e := range node.GetExprs() {
+		Walk(v, e)
+	}
+	v.Visit(nil)
+}
+
+type inspector func(Expressioner) bool
+
+func (f inspector) Visit(node Expressioner) Visitor {
+	if f(node) {
+		return f
+	}
+	return nil
+}
+
+// Inspect traverses an expression tree in depth-first order,
+// it calls f(node) for each node, the children of node are visited when f returns true.
+// After the children, f(nil) is called.
+func Inspect(node Expressioner, f func(Expressioner) bool) {
+	Walk(inspector(f), node)
+}
+
+// InspectWithParents is like Inspect,
+// it also gives to f the parents of node, from the root to the direct parent.
+// parents must not be retained after f returns, copy it instead.
+func InspectWithParents(node Expressioner, f func(node Expressioner, parents []Expressioner) bool) {
+	parents := []Expressioner{}
+	Inspect(node, func(n Expressioner) bool {
+		if n == nil {
+			parents = parents[:len(parents)-1]
+			return false
+		}
+		if !f(n, parents) {
+			return false
+		}
+		parents = append(parents, n)
+		return true
+	})
+}
+
+// Collect returns the nodes of the tree for which f returns true,
+// in depth-first order.
+func Collect(node Expressioner, f func(Expressioner) bool) []Expressioner {
+	ret := []Expressioner{}
+	Inspect(node, func(n Expressioner) bool {
+		if n != nil && f(n) {
+			ret = append(ret, n)
+		}
+		return true
+	})
+	return ret
+}
+
+// Parents indexes the direct parent of each node of the tree,
+// the root has no parent.
+type Parents map[Expressioner]Expressioner
+
+// NewParents indexes the parents of the nodes of root.
+func NewParents(root Expressioner) Parents {
+	ret := Parents{}
+	InspectWithParents(root, func(n Expressioner, parents []Expressioner) bool {
+		if len(parents) > 0 {
+			ret[n] = parents[len(parents)-1]
+		}
+		return true
+	})
+	return ret
+}
+
+// Parent returns the direct parent of node, or nil.
+func (p Parents) Parent(node Expressioner) Expressioner {
+	return p[node]
+}
+
+// Ancestors returns the parents of node, from the direct parent to the root.
+func (p Parents) Ancestors(node Expressioner) []Expressioner {
+	ret := []Expressioner{}
+	for n := p[node]; n != nil; n = p[n] {
+		ret = append(ret, n)
+	}
+	return ret
+}
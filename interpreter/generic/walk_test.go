@@ -0,0 +1,92 @@
+package generic
+
+import (
+	"testing"
+
+	genericlexer "github.com/mh-cbon/gigo/lexer/generic"
+	lexer "github.com/mh-cbon/state-lexer"
+)
+
+func makeWalkTree() (*Expression, *Expression, *TokenWithPos) {
+	word := func(v string) *TokenWithPos {
+		return NewTokenWithPos(lexer.Token{Type: genericlexer.WordToken, Value: v}, 1, 0)
+	}
+	leaf := word("c")
+	inner := &Expression{}
+	inner.AddExpr(word("b"))
+	inner.AddExpr(leaf)
+	root := &Expression{}
+	root.AddExpr(word("a"))
+	root.AddExpr(inner)
+	root.AddExpr(word("d"))
+	return root, inner, leaf
+}
+
+func TestInspect(t *testing.T) {
+	root, inner, _ := makeWalkTree()
+
+	got := ""
+	Inspect(root, func(n Expressioner) bool {
+		if n == nil {
+			got += ")"
+		} else if len(n.GetExprs()) > 0 {
+			got += "("
+		} else {
+			got += n.String()
+		}
+		return true
+	})
+	// leaves are followed by their own f(nil).
+	want := "(a)(b)c))d))"
+	if got != want {
+		t.Errorf("unexpected walk wanted=%q, got=%q", want, got)
+	}
+
+	got = ""
+	Inspect(root, func(n Expressioner) bool {
+		if n != nil && len(n.GetExprs()) == 0 {
+			got += n.String()
+		}
+		return n != inner
+	})
+	want = "ad"
+	if got != want {
+		t.Errorf("unexpected walk wanted=%q, got=%q", want, got)
+	}
+}
+
+func TestInspectWithParents(t *testing.T) {
+	root, inner, leaf := makeWalkTree()
+
+	var leafParents []Expressioner
+	InspectWithParents(root, func(n Expressioner, parents []Expressioner) bool {
+		if n == leaf {
+			leafParents = append(leafParents, parents...)
+		}
+		if n.String() == "d" && len(parents) != 1 {
+			t.Errorf("unexpected parents len wanted=%v, got=%v", 1, len(parents))
+		}
+		return true
+	})
+	if len(leafParents) != 2 || leafParents[0] != root || leafParents[1] != inner {
+		t.Errorf("unexpected parents wanted=%q, got=%q", []Expressioner{root, inner}, leafParents)
+	}
+
+	parents := NewParents(root)
+	if parents.Parent(leaf) != inner {
+		t.Errorf("unexpected parent wanted=%q, got=%q", inner, parents.Parent(leaf))
+	}
+	if parents.Parent(root) != nil {
+		t.Errorf("unexpected parent wanted=%v, got=%q", nil, parents.Parent(root))
+	}
+	if a := parents.Ancestors(leaf); len(a) != 2 || a[0] != inner || a[1] != root {
+		t.Errorf("unexpected ancestors wanted=%q, got=%q", []Expressioner{inner, root}, a)
+	}
+
+	words := Collect(root, func(n Expressioner) bool {
+		return len(n.GetExprs()) == 0
+	})
+	if len(words) != 4 || words[2] != leaf {
+		t.Errorf("unexpected collect wanted=%q, got=%q", "abcd", words)
+	}
+}
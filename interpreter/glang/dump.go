@@ -1,6 +1,8 @@
 package glang
 
 import (
+	"io"
+
 	genericinterpreter "github.com/mh-cbon/gigo/interpreter/generic"
 	glanglexer "github.com/mh-cbon/gigo/lexer/glang"
 )
@@ -14,3 +16,13 @@ func Dump(src genericinterpreter.Expressioner) {
 func DumpTokens(tokens []genericinterpreter.Tokener) {
 	genericinterpreter.DumpTokensWithNamer(tokens, glanglexer.TokenType)
 }
+
+// DumpJSON writes the tree of src as json.
+func DumpJSON(w io.Writer, src genericinterpreter.Expressioner) error {
+	return genericinterpreter.DumpJSON(w, src, glanglexer.TokenType)
+}
+
+// DumpDot writes the tree of src as a graphviz digraph.
+func DumpDot(w io.Writer, src genericinterpreter.Expressioner) error {
+	return genericinterpreter.DumpDot(w, src, glanglexer.TokenType)
+}
@@ -92,6 +92,16 @@ func (I *GigoInterpreter) Process(withpkgdcl bool) error {
 				return err
 			}
 
+			// type Slice[T any] struct {}
+			var typeParams *glang.PropsBlockDecl
+			if I.Peek(glanglexer.BracketOpenToken) != nil {
+				typeParams, err = I.ReadParenDecl(false, glanglexer.BracketOpenToken, glanglexer.BracketCloseToken)
+				if err != nil {
+					return err
+				}
+				typeParams.AddExprs(I.Emit())
+			}
+
 			I.ReadWs(true, true)
 
 			if typeTok := I.Peek(glanglexer.StructToken); typeTok != nil {
@@ -101,13 +111,17 @@ func (I *GigoInterpreter) Process(withpkgdcl bool) error {
 					return err
 				}
 				sDecl.Name = name
+				if typeParams != nil {
+					sDecl.TypeParams = typeParams
+					sDecl.PrependExpr(typeParams)
+				}
 				sDecl.PrependExpr(name)
 				sDecl.PrependExprs(preTokens)
 				I.Scope.AddExpr(sDecl)
 
 			} else if typeTok := I.Peek(glanglexer.InterfaceToken); typeTok != nil {
 
-				sDecl, err := I.ReadInterfaceDecl()
+				sDecl, err := I.ReadInterfaceDecl(false)
 				if err != nil {
 					return err
 				}
@@ -126,6 +140,23 @@ func (I *GigoInterpreter) Process(withpkgdcl bool) error {
 				implDecl.PrependExpr(name)
 				implDecl.PrependExprs(preTokens)
 				I.Scope.AddExpr(implDecl)
+
+			} else {
+				// type TodoSlice = Slice[Todo], type Names []string
+				I.Read(glanglexer.AssignToken)
+				I.ReadWs(true, true)
+				typeName, err := I.ReadTypeName(false, true)
+				if err != nil {
+					return err
+				}
+				tDecl := glang.NewExpressionDecl()
+				tDecl.AddExprs(preTokens)
+				tDecl.AddExpr(name)
+				if typeParams != nil {
+					tDecl.AddExpr(typeParams)
+				}
+				tDecl.AddExpr(typeName)
+				I.Scope.AddExpr(tDecl)
 			}
 
 		} else if tok := I.Peek(glanglexer.ImportToken); tok != nil {
@@ -133,17 +164,37 @@ func (I *GigoInterpreter) Process(withpkgdcl bool) error {
 			I.Read(glanglexer.ImportToken)
 			I.ReadWs(true, true, glanglexer.NlToken)
 			I.Scope.AddExprs(I.Emit())
-			I.ReadBlock(glanglexer.ParenOpenToken, glanglexer.ParenCloseToken)
+			if I.Peek(glanglexer.ParenOpenToken) != nil {
+				I.ReadBlock(glanglexer.ParenOpenToken, glanglexer.ParenCloseToken)
+			} else {
+				// import "fmt", import f "fmt"
+				if I.Read(genericlexer.WordToken, glanglexer.DotToken) != nil {
+					I.ReadMany(genericlexer.WsToken)
+				}
+				I.Read(genericlexer.TextToken)
+			}
 			I.ReadWs(true, true, glanglexer.NlToken)
 			I.Scope.AddExprs(I.Emit())
 
 		} else if tok := I.Peek(glanglexer.TemplateToken); tok != nil {
 
-			tplDecl, err := I.ReadTemplateDecl()
-			if err != nil {
-				return err
+			I.Read(glanglexer.TemplateToken)
+			isExtra := I.Peek(glanglexer.ParenOpenToken) != nil
+			I.Rewind()
+
+			if isExtra {
+				extraDecl, err := I.ReadTemplateExtraDecl()
+				if err != nil {
+					return err
+				}
+				I.Scope.AddExpr(extraDecl)
+			} else {
+				tplDecl, err := I.ReadTemplateDecl()
+				if err != nil {
+					return err
+				}
+				I.Scope.AddExpr(tplDecl)
 			}
-			I.Scope.AddExpr(tplDecl)
 
 		} else if tok := I.Peek(glanglexer.TplOpenToken); tok != nil {
 			block, err := I.ReadTemplateExprDecl()
@@ -304,6 +355,11 @@ func (I *GigoInterpreter) ReadPropsBlock(
 					if err2 == nil && x != nil {
 						ret.AddUnderlying(x)
 						ret.AddExpr(x)
+						tag, err3 := I.readPropTag(ret)
+						if err3 != nil {
+							return nil, err3
+						}
+						ret.SetUnderlyingTag(x, tag)
 						I.ReadWs(true, true)
 						ret.AddExprs(I.Emit())
 						continue
@@ -322,6 +378,11 @@ func (I *GigoInterpreter) ReadPropsBlock(
 					ret.AddUnderlying(x)
 					ret.AddExpr(x)
 					ret.AddExprs(ws2)
+					tag, err := I.readPropTag(ret)
+					if err != nil {
+						return nil, err
+					}
+					ret.SetUnderlyingTag(x, tag)
 				} else {
 					if err != nil {
 						return nil, err
@@ -330,6 +391,11 @@ func (I *GigoInterpreter) ReadPropsBlock(
 					propdecl.AddExpr(ID)
 					propdecl.AddExprs(ws2)
 					propdecl.AddExpr(IDType)
+					tag, err := I.readPropTag(propdecl)
+					if err != nil {
+						return nil, err
+					}
+					propdecl.Tag = tag
 					ret.AddExpr(propdecl)
 				}
 				I.ReadWs(true, true)
@@ -345,6 +411,36 @@ func (I *GigoInterpreter) ReadPropsBlock(
 	return ret, nil
 }
 
+// readPropTag reads the optional tag of a struct property.
+// The whitespaces preceding the tag are emitted into to,
+// it returns nil if there is no tag.
+func (I *GigoInterpreter) readPropTag(to genericinterperter.ExprReceiver) (*glang.TagDecl, error) {
+	I.ReadMany(genericlexer.WsToken)
+	if I.Peek(genericlexer.TextToken) == nil {
+		return nil, nil
+	}
+	to.AddExprs(I.Emit())
+	tag, err := I.ReadTagDecl()
+	if err != nil {
+		return nil, err
+	}
+	to.AddExpr(tag)
+	return tag, nil
+}
+
+// ReadTagDecl reads a struct property tag.
+// the next token must be a TextToken
+// returns an error if none is found.
+// Name string `json:"name"`
+func (I *GigoInterpreter) ReadTagDecl() (*glang.TagDecl, error) {
+	if I.Read(genericlexer.TextToken) == nil {
+		return nil, I.Debug("unexpected token", genericlexer.TextToken)
+	}
+	ret := glang.NewTagDecl()
+	ret.AddExprs(I.Emit())
+	return ret, nil
+}
+
 // ReadAssignsBlock reads a block of asignment.
 // returns an error if none is found.
 // The next token to analyze must be of type open,
@@ -460,6 +556,7 @@ func (I *GigoInterpreter) ReadAssignsBlock(
 // In between data are read as a golang block of properties,
 // type interface { f() }
 func (I *GigoInterpreter) ReadSignsBlock(
+	templated bool,
 	open lexer.TokenType,
 	close lexer.TokenType,
 ) (*glang.SignsBlockDecl, error) {
@@ -490,7 +587,7 @@ func (I *GigoInterpreter) ReadSignsBlock(
 		} else {
 			ret.AddExprs(I.Emit())
 
-			if ID, err := I.ReadVarName(false, false, false); ID != nil {
+			if ID, err := I.ReadVarName(templated, false, false); ID != nil {
 				if err != nil {
 					return ret, err
 				}
@@ -500,7 +597,7 @@ func (I *GigoInterpreter) ReadSignsBlock(
 					I.ReadMany(glanglexer.NlToken)
 					ret.AddExprs(I.Emit())
 				} else {
-					sign, err := I.ReadFuncSign(ID)
+					sign, err := I.ReadFuncSign(templated, ID)
 					if err != nil {
 						return nil, err
 					}
@@ -558,7 +655,24 @@ func (I *GigoInterpreter) ReadParenDecl(
 			ws := I.ReadWs(true, true)
 			I.Emit()
 
-			ID, err := I.ReadVarName(templated, false, false)
+			if I.isTypeStart() {
+				// an unnamed type such as *T, []T, ...T, func(), map[K]V
+				IDType, err := I.ReadTypeName(templated, true)
+				if err != nil {
+					return nil, err
+				}
+				IDType.PrependExprs(ws)
+				propdecl := ret.AddT(IDType)
+				ret.AddExpr(propdecl)
+				I.ReadWs(true, true)
+				ret.AddExprs(I.Emit())
+				I.ReadMany(glanglexer.NlToken)
+				continue
+			}
+
+			// a name, or an unnamed type such as pkg.T, (pkg.T, error),
+			// a name can be blank, (_ string).
+			ID, err := I.ReadVarName(templated, true, true)
 			if err != nil {
 				return nil, err
 			}
@@ -641,13 +755,13 @@ func (I *GigoInterpreter) ReadBodyBlock(open lexer.TokenType, close lexer.TokenT
 // ReadFuncSign reads a func signature.
 // returns an error if none is found.
 // AddExpr(expr Tokener)
-func (I *GigoInterpreter) ReadFuncSign(ID *glang.IdentifierDecl) (*glang.FuncDecl, error) {
+func (I *GigoInterpreter) ReadFuncSign(templated bool, ID *glang.IdentifierDecl) (*glang.FuncDecl, error) {
 	ret := glang.NewFuncDecl()
 	ret.Name = ID
 	ret.AddExpr(ID)
 
 	I.ReadWs(true, true)
-	params, err := I.ReadParenDecl(false, glanglexer.ParenOpenToken, glanglexer.ParenCloseToken)
+	params, err := I.ReadParenDecl(templated, glanglexer.ParenOpenToken, glanglexer.ParenCloseToken)
 	if err != nil {
 		return nil, err
 	}
@@ -656,24 +770,21 @@ func (I *GigoInterpreter) ReadFuncSign(ID *glang.IdentifierDecl) (*glang.FuncDec
 	ret.AddExpr(params)
 
 	I.ReadWs(true, true)
-	out, _ := I.ReadParenDecl(false, glanglexer.ParenOpenToken, glanglexer.ParenCloseToken)
+	out, _ := I.ReadParenDecl(templated, glanglexer.ParenOpenToken, glanglexer.ParenCloseToken)
 	if out != nil {
 		out.AddExprs(I.Emit())
 		ret.Out = out
 		ret.AddExpr(out)
 	} else {
-		outTok, err := I.ReadTypeIdentifier(false)
-		if err != nil {
-			return nil, err
-		}
+		// its a non paren Out like p(...) error
+		outTok, _ := I.ReadTypeName(templated, true)
 		if outTok != nil {
 			out := glang.NewPropsBlockDecl()
 			out.AddT(outTok)
 			out.AddExpr(outTok)
+			out.AddExprs(I.Emit())
 			ret.Out = out
-			ret.AddExprs(I.Emit())
-			// its a non paren Out like func p(...) error {}
-			// panic("unhandled so far")
+			ret.AddExpr(out)
 		}
 	}
 
@@ -713,56 +824,146 @@ func (I *GigoInterpreter) ReadExpressionsBlock(templated bool, open lexer.TokenT
 			}
 
 		} else {
+			stmt, err := I.readStmt(templated, close)
+			if err != nil {
+				return nil, err
+			}
+			if stmt != nil {
+				ret.AddExpr(stmt)
+			} else if I.Next() == nil {
+				return nil, I.Debug("require token", close)
+			}
+			// <-time.After(time.Second * 2)
 
-			if I.Peek(glanglexer.VarToken) != nil {
-				varDecl, err := I.ReadVarDecl(templated)
-				if err != nil {
-					return nil, err
-				}
-				ret.AddExpr(varDecl)
-			} else if I.Peek(glanglexer.ReturnToken) != nil {
-				r, err := I.ReadReturnDecl(templated)
-				if err != nil {
-					return nil, err
-				}
-				ret.AddExpr(r)
+		}
+	}
+	ret.AddExprs(I.Emit())
+	return ret, nil
+}
 
-			} else if I.Peek(genericlexer.WordToken) != nil {
-				expr, err := I.ReadExpressionBlock(templated, glanglexer.NlToken, close)
-				if err != nil {
-					return nil, err
-				}
-				ret.AddExpr(expr)
+// readStmt reads a statement of a block body,
+// expressions end with a new line or any of until.
+// It returns nil if the next token does not start a known statement.
+func (I *GigoInterpreter) readStmt(templated bool, until ...lexer.TokenType) (genericinterperter.Tokener, error) {
 
-			} else if I.Peek(glanglexer.ForToken) != nil {
-				expr, err := I.ReadForBlock(templated)
-				if err != nil {
-					return nil, err
-				}
-				ret.AddExpr(expr)
+	if I.Peek(glanglexer.VarToken) != nil {
+		return I.ReadVarDecl(templated)
 
-			} else if I.Peek(glanglexer.IfToken) != nil {
-				expr, err := I.ReadIfStmt(templated)
-				if err != nil {
-					return nil, err
-				}
-				ret.AddExpr(expr)
+	} else if I.Peek(glanglexer.ReturnToken) != nil {
+		return I.ReadReturnDecl(templated)
 
-			} else if I.Peek(glanglexer.SwitchToken) != nil {
-				expr, err := I.ReadSwitchStmt(templated)
-				if err != nil {
-					return nil, err
-				}
-				ret.AddExpr(expr)
+	} else if I.isLabel() {
+		return I.ReadLabeledStmt(templated, until...)
 
-			} else if I.Next() == nil {
-				return nil, I.Debug("require token", close)
-			}
-			// <-time.After(time.Second * 2)
+	} else if I.Peek(genericlexer.WordToken) != nil ||
+		templated && I.Peek(glanglexer.TplOpenToken) != nil {
+		x := append([]lexer.TokenType{glanglexer.NlToken}, until...)
+		return I.ReadExpressionBlock(templated, x...)
+
+	} else if I.Peek(glanglexer.GoToken, glanglexer.DeferToken) != nil {
+		ret := glang.NewExpressionDecl()
+		I.Read(glanglexer.GoToken, glanglexer.DeferToken)
+		I.ReadWs(true, true)
+		ret.AddExprs(I.Emit())
+		x := append([]lexer.TokenType{glanglexer.NlToken}, until...)
+		expr, err := I.ReadExpressionBlock(templated, x...)
+		if err != nil {
+			return nil, err
+		}
+		ret.AddExpr(expr)
+		return ret, nil
+
+	} else if I.Peek(glanglexer.GotoToken, glanglexer.BreakToken, glanglexer.ContinueToken) != nil {
+		return I.ReadJumpStmt(templated)
+
+	} else if I.Peek(glanglexer.ForToken) != nil {
+		return I.ReadForBlock(templated)
+
+	} else if I.Peek(glanglexer.IfToken) != nil {
+		return I.ReadIfStmt(templated)
+
+	} else if I.isTypeSwitch() {
+		return I.ReadTypeSwitchStmt(templated)
+
+	} else if I.Peek(glanglexer.SwitchToken) != nil {
+		return I.ReadSwitchStmt(templated)
+	}
+
+	return nil, nil
+}
+
+// isLabel returns true if the next tokens are a label such as outer:
+func (I *GigoInterpreter) isLabel() bool {
+	next := I.PeekN(2)
+	return next[0] != nil && next[0].GetType() == genericlexer.WordToken &&
+		next[1] != nil && next[1].GetType() == glanglexer.ColonToken
+}
+
+// isTypeSwitch returns true if the next tokens are a switch on a type,
+// switch x.(type) {
+func (I *GigoInterpreter) isTypeSwitch() bool {
+	if I.Read(glanglexer.SwitchToken) == nil {
+		return false
+	}
+	whatis := I.PeekUntil(glanglexer.TypeToken, glanglexer.BraceOpenToken)
+	I.RewindAll()
+	return whatis != nil && whatis.GetType() == glanglexer.TypeToken
+}
+
+// ReadLabeledStmt reads a label and the statement it labels.
+// the next tokens must be a word followed by a ColonToken
+// outer: for {}
+func (I *GigoInterpreter) ReadLabeledStmt(templated bool, until ...lexer.TokenType) (*glang.LabeledStmt, error) {
+	ret := glang.NewLabeledStmt()
+
+	label, err := I.ReadVarName(templated, false, false)
+	if err != nil {
+		return nil, err
+	}
+	ret.Label = label
+	ret.AddExpr(label)
+
+	if I.Read(glanglexer.ColonToken) == nil {
+		return nil, I.Debug("unexpected token", glanglexer.ColonToken)
+	}
+	I.ReadWs(true, true, glanglexer.NlToken)
+	ret.AddExprs(I.Emit())
 
+	if I.Peek(until...) == nil {
+		stmt, err := I.readStmt(templated, until...)
+		if err != nil {
+			return nil, err
+		}
+		if stmt != nil {
+			ret.Stmt = stmt
+			ret.AddExpr(stmt)
 		}
 	}
+	return ret, nil
+}
+
+// ReadJumpStmt reads a goto, break or continue statement and its label.
+// break outer
+func (I *GigoInterpreter) ReadJumpStmt(templated bool) (*glang.JumpStmt, error) {
+	ret := glang.NewJumpStmt()
+
+	ret.Keyword = I.Read(glanglexer.GotoToken, glanglexer.BreakToken, glanglexer.ContinueToken)
+	if ret.Keyword == nil {
+		return nil, I.Debug("unexpected token", glanglexer.GotoToken, glanglexer.BreakToken, glanglexer.ContinueToken)
+	}
+	I.ReadWs(true, true)
 	ret.AddExprs(I.Emit())
+
+	if I.Peek(genericlexer.WordToken) != nil {
+		label, err := I.ReadVarName(templated, false, false)
+		if err != nil {
+			return nil, err
+		}
+		ret.Label = label
+		ret.AddExpr(label)
+	} else if ret.Keyword.GetType() == glanglexer.GotoToken {
+		return nil, I.Debug("unexpected token", genericlexer.WordToken)
+	}
 	return ret, nil
 }
 
@@ -784,49 +985,12 @@ func (I *GigoInterpreter) ReadExpressionsBlockUntil(templated bool, until ...lex
 			break
 
 		} else {
-
-			if I.Peek(glanglexer.VarToken) != nil {
-				varDecl, err := I.ReadVarDecl(templated)
-				if err != nil {
-					return nil, err
-				}
-				ret.AddExpr(varDecl)
-			} else if I.Peek(glanglexer.ReturnToken) != nil {
-				r, err := I.ReadReturnDecl(templated)
-				if err != nil {
-					return nil, err
-				}
-				ret.AddExpr(r)
-
-			} else if I.Peek(genericlexer.WordToken) != nil {
-				x := append([]lexer.TokenType{glanglexer.NlToken}, until...)
-				expr, err := I.ReadExpressionBlock(templated, x...)
-				if err != nil {
-					return nil, err
-				}
-				ret.AddExpr(expr)
-
-			} else if I.Peek(glanglexer.ForToken) != nil {
-				expr, err := I.ReadForBlock(templated)
-				if err != nil {
-					return nil, err
-				}
-				ret.AddExpr(expr)
-
-			} else if I.Peek(glanglexer.IfToken) != nil {
-				expr, err := I.ReadIfStmt(templated)
-				if err != nil {
-					return nil, err
-				}
-				ret.AddExpr(expr)
-
-			} else if I.Peek(glanglexer.SwitchToken) != nil {
-				expr, err := I.ReadSwitchStmt(templated)
-				if err != nil {
-					return nil, err
-				}
-				ret.AddExpr(expr)
-
+			stmt, err := I.readStmt(templated, until...)
+			if err != nil {
+				return nil, err
+			}
+			if stmt != nil {
+				ret.AddExpr(stmt)
 			} else if I.Next() == nil {
 				return nil, I.Debug("require token", until...)
 			}
@@ -870,6 +1034,11 @@ func (I *GigoInterpreter) ReadVarName(templated, allowunderscore, allowdot bool)
 			if I.Read(glanglexer.DotToken) == nil {
 				break
 			}
+			if I.Peek(glanglexer.ParenOpenToken) != nil {
+				// x.(T) is a type assertion.
+				I.Rewind()
+				break
+			}
 		} else {
 			break
 		}
@@ -912,26 +1081,29 @@ func (I *GigoInterpreter) ReadNumber() (*glang.IdentifierDecl, error) {
 	return ret, nil
 }
 
+// builtinTypeTokens are the token types of the builtin type names.
+var builtinTypeTokens = []lexer.TokenType{
+	glanglexer.StringToken,
+	glanglexer.IntToken,
+	glanglexer.Int8Token,
+	glanglexer.Int16Token,
+	glanglexer.Int32Token,
+	glanglexer.Int64Token,
+	glanglexer.UintToken,
+	glanglexer.Uint8Token,
+	glanglexer.Uint16Token,
+	glanglexer.Uint32Token,
+	glanglexer.Uint64Token,
+	glanglexer.FloatToken,
+	glanglexer.Float32Token,
+	glanglexer.Float64Token,
+}
+
 // ReadTypeIdentifier ...
 func (I *GigoInterpreter) ReadTypeIdentifier(templated bool) (*glang.ExpressionDecl, error) {
 	var ret *glang.ExpressionDecl
 
-	if p := I.Read(
-		glanglexer.StringToken,
-		glanglexer.IntToken,
-		glanglexer.Int8Token,
-		glanglexer.Int16Token,
-		glanglexer.Int32Token,
-		glanglexer.Int64Token,
-		glanglexer.UintToken,
-		glanglexer.Uint8Token,
-		glanglexer.Uint16Token,
-		glanglexer.Uint32Token,
-		glanglexer.Uint64Token,
-		glanglexer.FloatToken,
-		glanglexer.Float32Token,
-		glanglexer.Float64Token,
-	); p != nil {
+	if p := I.Read(builtinTypeTokens...); p != nil {
 		ret = glang.NewExpressionDecl()
 		ID := glang.NewIdentifierDecl()
 		ID.AddExprs(I.Emit())
@@ -954,11 +1126,121 @@ func (I *GigoInterpreter) ReadTypeIdentifier(templated bool) (*glang.ExpressionD
 			return nil, err
 		}
 		ret.AddExpr(block)
+
+	} else if p := I.Read(glanglexer.MapToken); p != nil {
+		// map[K]V
+		ret = glang.NewExpressionDecl()
+		ID := glang.NewIdentifierDecl()
+		if I.Read(glanglexer.BracketOpenToken) == nil {
+			return nil, I.Debug("unexpected token", glanglexer.BracketOpenToken)
+		}
+		ID.AddExprs(I.Emit())
+		// the key can be templated, map[<:.Name>]V
+		key, err := I.ReadTypeName(templated, true)
+		if err != nil {
+			return nil, err
+		}
+		ID.AddExpr(key)
+		if I.Read(glanglexer.BracketCloseToken) == nil {
+			return nil, I.Debug("unexpected token", glanglexer.BracketCloseToken)
+		}
+		ID.AddExprs(I.Emit())
+		ret.AddExpr(ID)
+		value, err := I.ReadTypeName(templated, true)
+		if err != nil {
+			return nil, err
+		}
+		ret.AddExpr(value)
+
+	} else if p := I.Read(glanglexer.FuncToken); p != nil {
+		// func(...) results
+		ret = glang.NewExpressionDecl()
+		I.ReadWs(true, true)
+		ret.AddExprs(I.Emit())
+		params, err := I.ReadParenDecl(templated, glanglexer.ParenOpenToken, glanglexer.ParenCloseToken)
+		if err != nil {
+			return nil, err
+		}
+		params.AddExprs(I.Emit())
+		ret.AddExpr(params)
+		ws := I.ReadWs(false, false)
+		if I.Peek(glanglexer.ParenOpenToken) != nil {
+			ret.AddExprs(I.Emit())
+			out, err := I.ReadParenDecl(templated, glanglexer.ParenOpenToken, glanglexer.ParenCloseToken)
+			if err != nil {
+				return nil, err
+			}
+			out.AddExprs(I.Emit())
+			ret.AddExpr(out)
+		} else if I.Peek(genericlexer.WordToken) != nil || I.isTypeStart() {
+			ret.AddExprs(I.Emit())
+			out, err := I.ReadTypeName(templated, true)
+			if err != nil {
+				return nil, err
+			}
+			ret.AddExpr(out)
+		} else {
+			for range ws {
+				I.Rewind()
+			}
+		}
+
+	} else if I.isChanType() {
+		// chan T, <-chan T, chan<- T
+		ret = glang.NewExpressionDecl()
+		ID := glang.NewIdentifierDecl()
+		I.Read(glanglexer.ArrowToken)
+		I.Read(glanglexer.ChanToken)
+		I.Read(glanglexer.ArrowToken)
+		I.ReadWs(true, true)
+		ID.AddExprs(I.Emit())
+		ret.AddExpr(ID)
+		value, err := I.ReadTypeName(templated, true)
+		if err != nil {
+			return nil, err
+		}
+		ret.AddExpr(value)
 	}
 
 	return ret, nil
 }
 
+// isTypeStart returns true if the next token starts
+// a type that can not be mistaken with a var name.
+func (I *GigoInterpreter) isTypeStart() bool {
+	return I.Peek(builtinTypeTokens...) != nil || I.Peek(
+		glanglexer.MulToken,
+		glanglexer.BracketOpenToken,
+		glanglexer.ElipseToken,
+		glanglexer.FuncToken,
+		glanglexer.MapToken,
+		glanglexer.StructToken,
+		glanglexer.InterfaceToken,
+	) != nil || I.isChanType()
+}
+
+// isTokenType returns true if T is one of Ts.
+func isTokenType(T lexer.TokenType, Ts ...lexer.TokenType) bool {
+	for _, t := range Ts {
+		if t == T {
+			return true
+		}
+	}
+	return false
+}
+
+// isChanType returns true if the next tokens are chan or <-chan.
+func (I *GigoInterpreter) isChanType() bool {
+	if I.Peek(glanglexer.ChanToken) != nil {
+		return true
+	}
+	if I.Peek(glanglexer.ArrowToken) != nil {
+		next := I.PeekN(2)
+		return next[1] != nil && next[1].GetType() == glanglexer.ChanToken
+	}
+	return false
+}
+
 // ReadIdent ...
 func (I *GigoInterpreter) ReadIdent(templated bool, allowunderscore bool) (*glang.ExpressionDecl, error) {
 	var ret *glang.ExpressionDecl
@@ -979,9 +1261,15 @@ func (I *GigoInterpreter) ReadTypeName(templated bool, brackets bool) (*glang.Ex
 
 	var ret *glang.ExpressionDecl
 
+	if brackets {
+		I.Read(glanglexer.ElipseToken)
+	}
 	if brackets && I.Peek(glanglexer.BracketOpenToken) != nil {
 		for {
 			if I.Read(glanglexer.BracketOpenToken) != nil {
+				I.ReadWs(true, true)
+				// [N], [...]
+				I.Read(glanglexer.ElipseToken, genericlexer.WordToken)
 				I.ReadWs(true, true)
 				I.Read(glanglexer.BracketCloseToken)
 				I.ReadWs(true, true)
@@ -1008,6 +1296,15 @@ func (I *GigoInterpreter) ReadTypeName(templated bool, brackets bool) (*glang.Ex
 		name.PrependExprs(I.Emit())
 	}
 
+	// Slice[T], Map[K, V]
+	if err == nil && ret != nil && brackets && I.Peek(glanglexer.BracketOpenToken) != nil {
+		args, err2 := I.ReadTypeArgs(templated)
+		err = err2
+		if args != nil {
+			ret.AddExpr(args)
+		}
+	}
+
 	if err != nil {
 		I.RewindAll()
 		return nil, err
@@ -1016,6 +1313,35 @@ func (I *GigoInterpreter) ReadTypeName(templated bool, brackets bool) (*glang.Ex
 	return ret, nil
 }
 
+// ReadTypeArgs reads the type arguments of a generic type, [T], [K, V].
+func (I *GigoInterpreter) ReadTypeArgs(templated bool) (*glang.ExpressionDecl, error) {
+	ret := glang.NewExpressionDecl()
+
+	if I.Read(glanglexer.BracketOpenToken) == nil {
+		return nil, I.Debug("missing type arguments", glanglexer.BracketOpenToken)
+	}
+	for {
+		I.ReadWs(true, true)
+		ret.AddExprs(I.Emit())
+		if I.Read(glanglexer.BracketCloseToken) != nil {
+			break
+		}
+		t, err := I.ReadTypeName(templated, true)
+		if err != nil {
+			return nil, err
+		}
+		if t == nil {
+			return nil, I.Debug("missing type argument", glanglexer.BracketCloseToken)
+		}
+		ret.AddExpr(t)
+		I.ReadWs(true, true)
+		I.Read(glanglexer.CommaToken)
+	}
+	ret.AddExprs(I.Emit())
+
+	return ret, nil
+}
+
 // ReadTypeValue ...
 func (I *GigoInterpreter) ReadTypeValue(templated bool) (*glang.ExpressionDecl, error) {
 	var ret *glang.ExpressionDecl
@@ -1210,10 +1536,102 @@ func (I *GigoInterpreter) ReadSwitchStmt(templated bool) (*glang.SwitchStmt, err
 	return ret, nil
 }
 
-// ReadBranchedStmtBlock ...
-func (I *GigoInterpreter) ReadBranchedStmtBlock(templated bool) (*glang.BranchedStmtBlock, error) {
+// ReadTypeSwitchStmt reads a switch on the type of a value.
+// the next token must be a SwitchToken
+// switch v := x.(type) {
+// case int, string:
+// }
+func (I *GigoInterpreter) ReadTypeSwitchStmt(templated bool) (*glang.TypeSwitchStmt, error) {
+	var ret *glang.TypeSwitchStmt
 
-	var ret *glang.BranchedStmtBlock
+	if I.Read(glanglexer.SwitchToken) == nil {
+		return nil, I.Debug("unexpected token", glanglexer.SwitchToken)
+	}
+
+	ret = glang.NewTypeSwitchStmt()
+	I.ReadWs(true, true)
+	ret.AddExprs(I.Emit())
+
+	whatis := I.PeekUntil(glanglexer.TypeAssignToken, glanglexer.BraceOpenToken)
+	I.RewindAll()
+
+	if whatis != nil && whatis.GetType() == glanglexer.TypeAssignToken {
+		init, err := I.ReadAssignExpr(templated, false, glanglexer.BraceOpenToken)
+		if err != nil {
+			return nil, err
+		}
+		ret.SetInit(init)
+		ret.AddExpr(init)
+		ret.Assert = glang.FindTypeAssert(init)
+		I.blockscope.AddVar(init.CollectVarNames()...)
+	} else {
+		cond, err := I.ReadExpressionBlock(templated, glanglexer.BraceOpenToken)
+		if err != nil {
+			return nil, err
+		}
+		ret.AddExpr(cond)
+		ret.Assert = glang.FindTypeAssert(cond)
+	}
+	if ret.Assert == nil || !ret.Assert.IsTypeSwitch() {
+		return nil, I.Debug("unexpected token", glanglexer.TypeToken)
+	}
+
+	I.ReadWs(true, true)
+	ret.AddExprs(I.Emit())
+
+	body, err := I.readBranchedStmtBlock(templated, I.readCaseTypes)
+	if err != nil {
+		return nil, err
+	}
+	ret.Body = body
+	ret.AddExpr(body)
+
+	I.ReadWs(true, true)
+	ret.AddExprs(I.Emit())
+
+	return ret, nil
+}
+
+// readCaseTypes reads the types of a case in a type switch,
+// until the ColonToken.
+func (I *GigoInterpreter) readCaseTypes(templated bool) (*glang.ExpressionDecl, error) {
+	ret := glang.NewExpressionDecl()
+	for {
+		I.ReadWs(true, true)
+		ret.AddExprs(I.Emit())
+		t, err := I.ReadTypeName(templated, true)
+		if err != nil {
+			return nil, err
+		}
+		if t == nil {
+			return nil, I.Debug("unexpected token", genericlexer.WordToken)
+		}
+		ret.AddExpr(t)
+		I.ReadWs(true, true)
+		if I.Read(glanglexer.CommaToken) == nil {
+			break
+		}
+		ret.AddExprs(I.Emit())
+	}
+	ret.AddExprs(I.Emit())
+	return ret, nil
+}
+
+// ReadBranchedStmtBlock ...
+func (I *GigoInterpreter) ReadBranchedStmtBlock(templated bool) (*glang.BranchedStmtBlock, error) {
+	return I.readBranchedStmtBlock(templated, func(templated bool) (*glang.ExpressionDecl, error) {
+		return I.ReadExpressionBlock(templated, glanglexer.ColonToken)
+	})
+}
+
+// readBranchedStmtBlock reads the branches of a switch,
+// readCond reads the condition of a case.
+func (I *GigoInterpreter) readBranchedStmtBlock(
+	templated bool,
+	readCond func(templated bool) (*glang.ExpressionDecl, error),
+) (*glang.BranchedStmtBlock, error) {
+
+	var ret *glang.BranchedStmtBlock
 
 	open := glanglexer.BraceOpenToken
 	close := glanglexer.BraceCloseToken
@@ -1244,7 +1662,7 @@ func (I *GigoInterpreter) ReadBranchedStmtBlock(templated bool) (*glang.Branched
 			if I.Read(glanglexer.CaseToken) != nil {
 				branch := glang.NewBranchStmt()
 				branch.AddExprs(I.Emit())
-				ex, err := I.ReadExpressionBlock(templated, glanglexer.ColonToken)
+				ex, err := readCond(templated)
 				if err != nil {
 					return nil, err
 				}
@@ -1428,141 +1846,557 @@ func (I *GigoInterpreter) ReadExpressionBlock(templated bool, until ...lexer.Tok
 
 	ret = glang.NewExpressionDecl()
 
+	// operand is true when an operand was read last,
+	// an operator that follows it is binary, otherwise it is unary.
+	operand := false
+
 	for {
 
-		if I.Peek(
-			glanglexer.StructToken,
-		) != nil {
-			block, err := I.ReadStructDecl(templated)
+		progress := false
+		ws := len(I.ReadWs(true, true))
+
+		if operand && I.isBinaryOp(until...) {
+			I.Next()
+			ret.AddExprs(I.Emit())
+			progress = true
+			operand = false
+		}
+
+		// a, b = ...
+		if !isTokenType(glanglexer.CommaToken, until...) && I.Read(glanglexer.CommaToken) != nil {
+			I.ReadWs(true, true)
+			ret.AddExprs(I.Emit())
+			progress = true
+			operand = false
+		}
+
+		ws += len(I.ReadWs(true, true))
+		if progress {
+			ret.AddExprs(I.Emit())
+			ws = 0
+		}
+
+		if I.isOperandStart() {
+			ret.AddExprs(I.Emit())
+			x, err := I.ReadOperand(templated)
 			if err != nil {
 				return nil, err
 			}
-			ret.AddExpr(block)
-			I.ReadWs(true, true)
-			I.ReadBlock(glanglexer.BraceOpenToken, glanglexer.BraceCloseToken)
-			ret.AddExprs(I.Emit())
+			ret.AddExpr(x)
+			progress = true
+			operand = true
+
+			if I.Read(glanglexer.IncToken, glanglexer.DecToken) != nil {
+				ret.AddExprs(I.Emit())
+			} else if I.Read(
+				glanglexer.TypeAssignToken,
+				glanglexer.AssignToken,
+			) != nil {
+				ret.AddExprs(I.Emit())
+				operand = false
+			}
+		}
+
+		if !progress {
+			if len(ret.GetExprs()) == 0 && !I.isExpressionEnd(until...) {
+				// the next token can neither start nor end the expression.
+				return nil, I.Debug("unexpected token", genericlexer.WordToken)
+			}
+			// not part of the expression, leave it to the caller.
+			for i := 0; i < ws; i++ {
+				I.Rewind()
+			}
 			break
 		}
 
-		if I.Read(
-			genericlexer.TextToken,
-			glanglexer.TrueToken,
-			glanglexer.FalseToken,
-		) != nil {
-			ret.AddExprs(I.Emit())
+		if I.Peek(until...) != nil {
 			break
 		}
 
-		I.ReadWs(true, true)
+		if I.Peek(glanglexer.SemiColonToken) != nil {
+			break
+		}
 
-		if I.Read(
-			glanglexer.SubToken,
-			glanglexer.MulToken,
-			glanglexer.AddToken,
-			glanglexer.RemToken,
-			glanglexer.QuoToken,
-		) != nil {
-			ret.AddExprs(I.Emit())
+		if I.Peek(glanglexer.NlToken) != nil {
+			break
 		}
+	}
 
-		I.ReadWs(true, true)
+	return ret, nil
+}
 
-		if I.Peek(glanglexer.FuncToken) != nil {
-			block, err := I.ReadFuncDecl(templated, true)
-			if err != nil {
-				return nil, err
+// isExpressionEnd returns true if the next token ends an expression,
+// a new line, a semicolon, the end of the input or any of until.
+func (I *GigoInterpreter) isExpressionEnd(until ...lexer.TokenType) bool {
+	return I.PeekN(1)[0] == nil || I.Peek(until...) != nil ||
+		I.Peek(glanglexer.NlToken, glanglexer.SemiColonToken) != nil
+}
+
+// unaryOps are the token types of the operators of an UnaryExpr.
+var unaryOps = []lexer.TokenType{
+	glanglexer.SubToken,
+	glanglexer.AddToken,
+	glanglexer.NegateToken,
+	glanglexer.XorToken,
+	glanglexer.MulToken,
+	glanglexer.AndToken,
+	glanglexer.ArrowToken,
+}
+
+// binaryOps are the token types of the operators between two operands,
+// a <- b is a send statement.
+var binaryOps = []lexer.TokenType{
+	glanglexer.AddToken,
+	glanglexer.SubToken,
+	glanglexer.MulToken,
+	glanglexer.QuoToken,
+	glanglexer.RemToken,
+	glanglexer.AndToken,
+	glanglexer.OrToken,
+	glanglexer.XorToken,
+	glanglexer.ShlToken,
+	glanglexer.ShrToken,
+	glanglexer.AndNotToken,
+	glanglexer.ArrowToken,
+	glanglexer.EqToken,
+	glanglexer.NeqToken,
+	glanglexer.SmallerToken,
+	glanglexer.GreaterToken,
+	glanglexer.SmeqToken,
+	glanglexer.GteqToken,
+	glanglexer.LAndToken,
+	glanglexer.LOrToken,
+}
+
+// isBinaryOp returns true if the next token is a binary operator,
+// operators listed in until are left to the caller.
+func (I *GigoInterpreter) isBinaryOp(until ...lexer.TokenType) bool {
+	p := I.Peek(binaryOps...)
+	return p != nil && !isTokenType(p.GetType(), until...)
+}
+
+// isOperandStart returns true if the next token can start an operand.
+func (I *GigoInterpreter) isOperandStart() bool {
+	return I.Peek(unaryOps...) != nil || I.isChanType() || I.Peek(builtinTypeTokens...) != nil || I.Peek(
+		genericlexer.TextToken,
+		glanglexer.TrueToken,
+		glanglexer.FalseToken,
+		glanglexer.FuncToken,
+		glanglexer.MapToken,
+		glanglexer.StructToken,
+		glanglexer.BracketOpenToken,
+		glanglexer.ParenOpenToken,
+		glanglexer.TplOpenToken,
+		genericlexer.WordToken,
+	) != nil
+}
+
+// ReadOperand reads an operand such as x, "text", f(), x[i], T{}, []int{1}, -x, func(){},
+// string(b), struct{A int}{A: 1}.
+func (I *GigoInterpreter) ReadOperand(templated bool) (genericinterperter.Tokener, error) {
+
+	if I.isChanType() || I.Peek(glanglexer.MapToken, glanglexer.BracketOpenToken) != nil {
+		// chan T, map[K]V{}, []T{}
+		Type, err := I.ReadTypeName(templated, true)
+		if err != nil {
+			return nil, err
+		}
+		if I.Peek(glanglexer.BraceOpenToken) != nil {
+			return I.ReadCompositeLit(templated, Type)
+		}
+		return Type, nil
+	}
+
+	if I.Peek(unaryOps...) != nil {
+		return I.ReadUnaryExpr(templated)
+	}
+
+	if I.Read(
+		genericlexer.TextToken,
+		glanglexer.TrueToken,
+		glanglexer.FalseToken,
+	) != nil {
+		ret := glang.NewExpressionDecl()
+		ret.AddExprs(I.Emit())
+		return ret, nil
+	}
+
+	if I.Peek(glanglexer.ParenOpenToken) != nil {
+		// (x)
+		return I.ReadParenExprBlock(templated)
+	}
+
+	if I.Peek(glanglexer.FuncToken) != nil {
+		block, err := I.ReadFuncLit(templated)
+		if err != nil {
+			return nil, err
+		}
+		if I.Peek(glanglexer.ParenOpenToken) == nil {
+			return block, nil
+		}
+		// func(){}()
+		ret := glang.NewExpressionDecl()
+		ret.AddExpr(block)
+		call, err := I.ReadParenExprBlock(templated)
+		if err != nil {
+			return nil, err
+		}
+		ret.AddExpr(call)
+		return ret, nil
+	}
+
+	if I.Peek(glanglexer.StructToken) != nil {
+		// struct{A int}{A: 1}
+		Type, err := I.ReadStructDecl(templated)
+		if err != nil {
+			return nil, err
+		}
+		if I.Peek(glanglexer.BraceOpenToken) != nil {
+			return I.ReadCompositeLit(templated, Type)
+		}
+		return Type, nil
+	}
+
+	var v *glang.IdentifierDecl
+	if I.Read(builtinTypeTokens...) != nil {
+		// string(b), int(x)
+		v = glang.NewIdentifierDecl()
+		v.AddExprs(I.Emit())
+
+	} else {
+		if I.Peek(glanglexer.TplOpenToken, genericlexer.WordToken) == nil {
+			return nil, I.Debug("unexpected token", glanglexer.TplOpenToken, genericlexer.WordToken)
+		}
+		x, err := I.ReadVarName(templated, true, true)
+		if err != nil {
+			n, err2 := I.ReadNumber()
+			if n != nil {
+				return n, nil
 			}
-			ret.AddExpr(block)
+			fmt.Println(err)
+			fmt.Println(I.PeekN(5))
+			fmt.Println(err2)
+			fmt.Println(templated)
+			panic(err)
 		}
+		v = x
+	}
 
-		if I.Peek(glanglexer.BracketOpenToken) != nil {
-			I.ReadBlock(glanglexer.BracketOpenToken, glanglexer.BracketCloseToken)
+	I.ReadWs(true, true)
+
+	doBraces := !I.blockscope.HasVar(v.GetVarName())
+
+	var ret genericinterperter.Tokener = v
+
+	if I.Peek(glanglexer.BraceOpenToken) != nil {
+		if v.GetSlugName() == "select" {
+			// select is not a keyword of the lexer, its block is kept as is.
+			ret := glang.NewExpressionDecl()
+			ret.AddExpr(v)
+			I.ReadBlock(glanglexer.BraceOpenToken, glanglexer.BraceCloseToken)
 			ret.AddExprs(I.Emit())
+			return ret, nil
+		}
+		if doBraces {
+			return I.ReadCompositeLit(templated, v)
 		}
+		I.ReadMany(genericlexer.WsToken)
+		return v, nil
 
-		if I.Peek(glanglexer.TplOpenToken, genericlexer.WordToken) != nil {
+	} else if I.Peek(glanglexer.ParenOpenToken) != nil {
+		callexpr, err := I.readCallExpr(templated, v)
+		if err != nil {
+			return nil, err
+		}
+		ret = callexpr
+	}
 
-			v, err := I.ReadVarName(templated, true, true)
+	// s[i], x.(T), f().(T), s[i].Name, x.(T).Method()
+	for {
+		if I.Peek(glanglexer.BracketOpenToken) != nil {
+			x := ret
+			if ws := I.Emit(); len(ws) > 0 {
+				e := glang.NewExpressionDecl()
+				e.AddExpr(ret)
+				e.AddExprs(ws)
+				x = e
+			}
+			index, err := I.ReadIndexExpr(templated, x)
 			if err != nil {
-				n, err2 := I.ReadNumber()
-				if n != nil {
-					ret.AddExpr(n)
-					continue
-				} else {
-					fmt.Println(err)
-					fmt.Println(I.PeekN(5))
-					fmt.Println(err2)
-					fmt.Println(templated)
-					panic(err)
-					break
+				return nil, err
+			}
+			ret = index
+
+		} else if I.isTypeAssert() {
+			assert, err := I.ReadTypeAssertExpr(templated, ret)
+			if err != nil {
+				return nil, err
+			}
+			ret = assert
+
+		} else if ret != genericinterperter.Tokener(v) && I.Read(glanglexer.DotToken) != nil {
+			x := glang.NewExpressionDecl()
+			x.AddExpr(ret)
+			x.AddExprs(I.Emit())
+			sel, err := I.ReadVarName(templated, false, false)
+			if err != nil {
+				return nil, err
+			}
+			if I.Peek(glanglexer.ParenOpenToken) != nil {
+				callexpr, err := I.readCallExpr(templated, sel)
+				if err != nil {
+					return nil, err
 				}
+				x.AddExpr(callexpr)
+			} else {
+				x.AddExpr(sel)
 			}
+			ret = x
 
-			I.ReadWs(true, true)
+		} else {
+			break
+		}
+		I.ReadWs(true, true)
+	}
 
-			doBraces := !I.blockscope.HasVar(v.GetVarName())
+	return ret, nil
+}
 
-			if I.Read(glanglexer.IncToken, glanglexer.DecToken) != nil {
-				ret.AddExpr(v)
-				ret.AddExprs(I.Emit())
+// readCallExpr reads the arguments of a call to ID,
+// the next token must be a ParenOpenToken.
+func (I *GigoInterpreter) readCallExpr(templated bool, ID *glang.IdentifierDecl) (*glang.CallExpr, error) {
+	ret := glang.NewCallExpr()
+	ret.ID = ID
+	ret.AddExpr(ID)
+	ret.AddExprs(I.Emit())
 
-			} else if I.Peek(glanglexer.BraceOpenToken) != nil {
-				ret.AddExpr(v)
-				if doBraces {
-					I.ReadBlock(glanglexer.BraceOpenToken, glanglexer.BraceCloseToken)
-					ret.AddExprs(I.Emit())
-				}
-				I.ReadMany(genericlexer.WsToken)
+	block, err := I.ReadParenExprBlock(templated)
+	if err != nil {
+		return nil, err
+	}
+	ret.Params = block
+	ret.AddExpr(block)
+	return ret, nil
+}
 
-			} else if I.Peek(glanglexer.ParenOpenToken) != nil {
+// ReadUnaryExpr reads an operator and its operand, -x, !ok, &T{}, *p, <-ch.
+func (I *GigoInterpreter) ReadUnaryExpr(templated bool) (*glang.UnaryExpr, error) {
 
-				callexpr := glang.NewCallExpr()
-				callexpr.ID = v
-				callexpr.AddExpr(v)
-				callexpr.AddExprs(I.Emit())
+	ret := glang.NewUnaryExpr()
 
-				block, err := I.ReadParenExprBlock(templated)
-				if err != nil {
-					return nil, err
-				}
-				callexpr.Params = block
-				callexpr.AddExpr(block)
+	ret.Op = I.Read(unaryOps...)
+	if ret.Op == nil {
+		return nil, I.Debug("unexpected token", unaryOps...)
+	}
+	I.ReadWs(true, true)
+	ret.AddExprs(I.Emit())
 
-				ret.AddExpr(callexpr)
+	if !I.isOperandStart() {
+		return nil, I.Debug("unexpected token", genericlexer.WordToken)
+	}
+	x, err := I.ReadOperand(templated)
+	if err != nil {
+		return nil, err
+	}
+	ret.X = x
+	ret.AddExpr(x)
 
-			} else if I.Peek(glanglexer.BracketOpenToken) != nil {
-				ret.AddExpr(v)
-				I.ReadBlock(glanglexer.BracketOpenToken, glanglexer.BracketCloseToken)
-				ret.AddExprs(I.Emit())
+	return ret, nil
+}
 
-			} else if I.Peek(
-				glanglexer.TypeAssignToken,
-				glanglexer.AssignToken,
-			) != nil {
-				ret.AddExpr(v)
-				I.Read(
-					glanglexer.TypeAssignToken,
-					glanglexer.AssignToken,
-				)
-				ret.AddExprs(I.Emit())
-			} else {
-				ret.AddExpr(v)
+// ReadCompositeLit reads the braces of a composite literal of given Type,
+// Type is nil when it is elided, []T{{a: 1}}.
+func (I *GigoInterpreter) ReadCompositeLit(templated bool, Type genericinterperter.Tokener) (*glang.CompositeLit, error) {
+
+	ret := glang.NewCompositeLit()
+	if Type != nil {
+		ret.Type = Type
+		ret.AddExpr(Type)
+	}
+	ret.AddExprs(I.Emit())
+
+	ret.Open = I.Read(glanglexer.BraceOpenToken)
+	if ret.Open == nil {
+		return nil, I.Debug("unexpected token", glanglexer.BraceOpenToken)
+	}
+	ret.AddExprs(I.Emit())
+
+	for {
+		I.ReadWs(true, true, glanglexer.NlToken)
+		ret.AddExprs(I.Emit())
+
+		if ret.Close = I.Read(glanglexer.BraceCloseToken); ret.Close != nil {
+			ret.AddExprs(I.Emit())
+			break
+		}
+
+		elt, err := I.readCompositeElt(templated)
+		if err != nil {
+			return nil, err
+		}
+		ret.AddElt(elt)
+		ret.AddExpr(elt)
+
+		I.ReadWs(true, true, glanglexer.NlToken)
+		if I.Read(glanglexer.CommaToken) == nil && I.Peek(glanglexer.BraceCloseToken) == nil {
+			return nil, I.Debug("unexpected token", glanglexer.CommaToken, glanglexer.BraceCloseToken)
+		}
+		ret.AddExprs(I.Emit())
+	}
+
+	return ret, nil
+}
+
+// readCompositeElt reads an element of a composite literal, a value or a KeyValueExpr.
+func (I *GigoInterpreter) readCompositeElt(templated bool) (genericinterperter.Tokener, error) {
+
+	readValue := func() (genericinterperter.Tokener, error) {
+		if I.Peek(glanglexer.BraceOpenToken) != nil {
+			return I.ReadCompositeLit(templated, nil)
+		}
+		return I.ReadExpressionBlock(templated,
+			glanglexer.ColonToken,
+			glanglexer.CommaToken,
+			glanglexer.BraceCloseToken,
+		)
+	}
+
+	value, err := readValue()
+	if err != nil {
+		return nil, err
+	}
+
+	I.ReadWs(true, true)
+	if I.Peek(glanglexer.ColonToken) == nil {
+		I.RewindAll()
+		return value, nil
+	}
+
+	ret := glang.NewKeyValueExpr()
+	ret.Key = value
+	ret.AddExpr(value)
+	I.Read(glanglexer.ColonToken)
+	I.ReadWs(true, true)
+	ret.AddExprs(I.Emit())
+
+	value, err = readValue()
+	if err != nil {
+		return nil, err
+	}
+	ret.Value = value
+	ret.AddExpr(value)
+
+	return ret, nil
+}
+
+// ReadIndexExpr reads the brackets that follow X,
+// it returns an IndexExpr for x[i] and a SliceExpr for x[i:j] or x[i:j:k].
+func (I *GigoInterpreter) ReadIndexExpr(templated bool, X genericinterperter.Tokener) (genericinterperter.Tokener, error) {
+
+	if I.Read(glanglexer.BracketOpenToken) == nil {
+		return nil, I.Debug("unexpected token", glanglexer.BracketOpenToken)
+	}
+	I.ReadWs(true, true)
+	open := I.Emit()
+
+	// parts are low, high and max, separated by colons.
+	parts := []*glang.ExpressionDecl{}
+	exprs := []genericinterperter.Tokener{}
+	for {
+		var part *glang.ExpressionDecl
+		if I.Peek(glanglexer.ColonToken, glanglexer.BracketCloseToken) == nil {
+			x, err := I.ReadExpressionBlock(templated,
+				glanglexer.ColonToken,
+				glanglexer.BracketCloseToken,
+			)
+			if err != nil {
+				return nil, err
 			}
+			part = x
+			exprs = append(exprs, x)
 		}
+		parts = append(parts, part)
 
-		if I.Peek(until...) != nil {
+		I.ReadWs(true, true)
+		if I.Read(glanglexer.BracketCloseToken) != nil {
+			exprs = append(exprs, I.Emit()...)
 			break
 		}
+		if I.Read(glanglexer.ColonToken) == nil || len(parts) > 2 {
+			return nil, I.Debug("unexpected token", glanglexer.ColonToken, glanglexer.BracketCloseToken)
+		}
+		I.ReadWs(true, true)
+		exprs = append(exprs, I.Emit()...)
+	}
 
-		if I.Peek(glanglexer.SemiColonToken) != nil {
-			break
+	if len(parts) == 1 {
+		if parts[0] == nil {
+			return nil, I.Debug("unexpected token", genericlexer.WordToken)
 		}
+		ret := glang.NewIndexExpr()
+		ret.X = X
+		ret.Index = parts[0]
+		ret.AddExpr(X)
+		ret.AddExprs(open)
+		ret.AddExprs(exprs)
+		return ret, nil
+	}
 
-		if I.Peek(glanglexer.NlToken) != nil {
-			break
+	ret := glang.NewSliceExpr()
+	ret.X = X
+	ret.Low = parts[0]
+	ret.High = parts[1]
+	if len(parts) == 3 {
+		ret.Slice3 = true
+		ret.Max = parts[2]
+	}
+	ret.AddExpr(X)
+	ret.AddExprs(open)
+	ret.AddExprs(exprs)
+	return ret, nil
+}
+
+// isTypeAssert returns true if the next tokens are .(
+func (I *GigoInterpreter) isTypeAssert() bool {
+	next := I.PeekN(2)
+	return next[0] != nil && next[0].GetType() == glanglexer.DotToken &&
+		next[1] != nil && next[1].GetType() == glanglexer.ParenOpenToken
+}
+
+// ReadTypeAssertExpr reads the type assertion of X.
+// the next tokens must be .(
+// x.(T), s[0].(T), f().(T) or x.(type)
+func (I *GigoInterpreter) ReadTypeAssertExpr(templated bool, X genericinterperter.Tokener) (*glang.TypeAssertExpr, error) {
+	ret := glang.NewTypeAssertExpr()
+	ret.X = X
+	ret.AddExpr(X)
+
+	if I.Read(glanglexer.DotToken) == nil {
+		return nil, I.Debug("unexpected token", glanglexer.DotToken)
+	}
+	if I.Read(glanglexer.ParenOpenToken) == nil {
+		return nil, I.Debug("unexpected token", glanglexer.ParenOpenToken)
+	}
+	I.ReadWs(true, true)
+	ret.AddExprs(I.Emit())
+
+	if I.Read(glanglexer.TypeToken) == nil {
+		t, err := I.ReadTypeName(templated, true)
+		if err != nil {
+			return nil, err
+		}
+		if t == nil {
+			return nil, I.Debug("unexpected token", glanglexer.TypeToken, genericlexer.WordToken)
 		}
+		ret.Type = t
+		ret.AddExpr(t)
 	}
 
+	I.ReadWs(true, true)
+	if I.Read(glanglexer.ParenCloseToken) == nil {
+		return nil, I.Debug("unexpected token", glanglexer.ParenCloseToken)
+	}
+	ret.AddExprs(I.Emit())
 	return ret, nil
 }
 
@@ -1824,6 +2658,10 @@ func (I *GigoInterpreter) ReadParenExprBlock(
 			if err != nil {
 				return nil, err
 			}
+			if len(block.GetExprs()) == 0 {
+				// no progress, the next token can not start a param.
+				return nil, I.Debug("unexpected token", close)
+			}
 			ret.AddExprs(I.Emit())
 			ret.AddExpr(block)
 
@@ -1931,6 +2769,20 @@ func (I *GigoInterpreter) ReadFuncDecl(templated bool, isLiteral bool) (*glang.F
 	return ret, nil
 }
 
+// ReadFuncLit reads an anonymous func.
+// the next token must be a FuncToken
+// func(a int) int { return a }
+func (I *GigoInterpreter) ReadFuncLit(templated bool) (*glang.FuncLit, error) {
+	fn, err := I.ReadFuncDecl(templated, true)
+	if err != nil {
+		return nil, err
+	}
+	ret := glang.NewFuncLit()
+	ret.Func = fn
+	ret.AddExpr(fn)
+	return ret, nil
+}
+
 // ReadStructDecl reads a struct with its props.
 // the next token must be a StructToken
 // returns an error if none is found.
@@ -1980,13 +2832,27 @@ func (I *GigoInterpreter) ReadTemplateDecl() (*glang.TemplateDecl, error) {
 	}
 	ret.Name = ID
 
+	// template <:.Name>Slice(keys ...field, sorted bool) struct{}
+	var params []genericinterperter.Tokener
+	if I.Peek(glanglexer.ParenOpenToken) != nil {
+		block, err := I.ReadParenDecl(false, glanglexer.ParenOpenToken, glanglexer.ParenCloseToken)
+		if err != nil {
+			return nil, err
+		}
+		block.AddExprs(I.Emit())
+		ret.Params = block
+		params = append(params, block)
+	}
+
 	I.ReadWs(true, true)
+	params = append(params, I.Emit()...)
 	if I.Peek(glanglexer.StructToken) != nil {
 		structDecl, err := I.ReadStructDecl(true)
 		if err != nil {
 			return ret, nil
 		}
 		structDecl.Name = ID
+		structDecl.PrependExprs(params)
 		structDecl.PrependExpr(ID)
 		ret.AddExpr(structDecl)
 		ret.Block = structDecl.Block
@@ -1996,6 +2862,99 @@ func (I *GigoInterpreter) ReadTemplateDecl() (*glang.TemplateDecl, error) {
 	return ret, nil
 }
 
+// ReadTemplateExtraDecl reads a declaration emitted along the type of a template.
+// the next token must be a TemplateToken followed by the template name in parens,
+// template(Slice) func New<:.Name>Slice() *<:.Name>Slice {}
+// template(Slice) const <:.Name>Max = 10
+// template(Slice) type <:.Name>Iter struct {}
+// returns an error if none is found.
+func (I *GigoInterpreter) ReadTemplateExtraDecl() (*glang.TemplateExtraDecl, error) {
+
+	if I.Read(glanglexer.TemplateToken) == nil {
+		return nil, I.Debug("unexpected token", glanglexer.TemplateToken)
+	}
+	if I.Read(glanglexer.ParenOpenToken) == nil {
+		return nil, I.Debug("unexpected token", glanglexer.ParenOpenToken)
+	}
+	I.ReadWs(false, false)
+
+	ret := glang.NewTemplateExtraDecl()
+	ret.AddExprs(I.Emit())
+
+	of, err := I.ReadVarName(false, false, false)
+	if err != nil {
+		return nil, err
+	}
+	ret.Of = of
+	ret.AddExpr(of)
+
+	I.ReadWs(false, false)
+	if I.Read(glanglexer.ParenCloseToken) == nil {
+		return nil, I.Debug("unexpected token", glanglexer.ParenCloseToken)
+	}
+	I.ReadWs(true, true)
+	ret.AddExprs(I.Emit())
+
+	var decl genericinterperter.Tokener
+	if I.Peek(glanglexer.FuncToken) != nil {
+		decl, err = I.ReadFuncDecl(true, false)
+	} else if I.Peek(glanglexer.ConstToken) != nil {
+		decl, err = I.ReadConstDecl(true)
+	} else if I.Peek(glanglexer.VarToken) != nil {
+		decl, err = I.ReadVarDecl(true)
+	} else if I.Peek(glanglexer.TypeToken) != nil {
+		decl, err = I.ReadTemplatedTypeDecl()
+	} else {
+		return nil, I.Debug("unexpected token", glanglexer.FuncToken, glanglexer.ConstToken, glanglexer.VarToken, glanglexer.TypeToken)
+	}
+	if err != nil {
+		return nil, err
+	}
+	ret.Decl = decl
+	ret.AddExpr(decl)
+
+	return ret, nil
+}
+
+// ReadTemplatedTypeDecl reads a struct or an interface type with a templated name.
+// the next token must be a TypeToken.
+// type <:.Name>Iter struct {}
+func (I *GigoInterpreter) ReadTemplatedTypeDecl() (genericinterperter.Tokener, error) {
+
+	if I.Read(glanglexer.TypeToken) == nil {
+		return nil, I.Debug("unexpected token", glanglexer.TypeToken)
+	}
+	I.ReadWs(true, true)
+	preTokens := I.Emit()
+
+	name, err := I.ReadVarName(true, false, false)
+	if err != nil {
+		return nil, err
+	}
+	I.ReadWs(true, true)
+
+	if I.Peek(glanglexer.StructToken) != nil {
+		sDecl, err := I.ReadStructDecl(true)
+		if err != nil {
+			return nil, err
+		}
+		sDecl.Name = name
+		sDecl.PrependExpr(name)
+		sDecl.PrependExprs(preTokens)
+		return sDecl, nil
+	} else if I.Peek(glanglexer.InterfaceToken) != nil {
+		iDecl, err := I.ReadInterfaceDecl(true)
+		if err != nil {
+			return nil, err
+		}
+		iDecl.Name = name
+		iDecl.PrependExpr(name)
+		iDecl.PrependExprs(preTokens)
+		return iDecl, nil
+	}
+	return nil, I.Debug("unexpected token", glanglexer.StructToken, glanglexer.InterfaceToken)
+}
+
 // ReadTemplateExprDecl ...
 func (I *GigoInterpreter) ReadTemplateExprDecl() (*glang.TemplateFuncDecl, error) {
 
@@ -2246,13 +3205,16 @@ func (I *GigoInterpreter) ReadReturnDecl(templated bool) (*glang.ReturnDecl, err
 		)
 		ret.AddExprs(I.Emit())
 
+		if I.isExpressionEnd(glanglexer.BraceCloseToken) {
+			// return without values.
+			I.Read(glanglexer.NlToken)
+			break
+		}
+
 		// should be a value identifier
 		ID, err := I.ReadExpressionBlock(templated, glanglexer.CommaToken)
 		if err != nil {
-			fmt.Printf("%+v", err)
-			fmt.Printf("%#v", err)
-			panic(err)
-			break
+			return nil, err
 		}
 		ret.AddExpr(ID)
 
@@ -2364,6 +3326,12 @@ func (I *GigoInterpreter) ReadImplDecl() (*glang.ImplementDecl, error) {
 	ret.AddExpr(implTemplate)
 	ret.ImplementTemplate = implTemplate
 
+	chains, err := I.ReadImplementChains(implTemplate)
+	if err != nil {
+		return nil, err
+	}
+	ret.Chains = chains
+
 	I.ReadMany(genericlexer.WsToken)
 	ret.AddExprs(I.Emit())
 
@@ -2376,11 +3344,197 @@ func (I *GigoInterpreter) ReadImplDecl() (*glang.ImplementDecl, error) {
 	return ret, nil
 }
 
+// ReadImplementChains reads the template block of an implements,
+// <:A .Todo, .Todo | Slice "Name" | Mutexed>,
+// it returns a chain per template expression separated by a comma,
+// the stages of a chain are separated by a |.
+// The chains replace the tokens of the block.
+// returns an error if an expression or a stage is empty,
+// or if a stage does not start with a template name.
+func (I *GigoInterpreter) ReadImplementChains(block *glang.BodyBlockDecl) ([]*glang.ImplementChain, error) {
+	var ret []*glang.ImplementChain
+
+	var tokens []genericinterperter.Tokener
+	inside := false
+	for _, t := range block.Tokens {
+		if t == block.Close {
+			inside = false
+		}
+		if inside {
+			tokens = append(tokens, t)
+		}
+		if t == block.Open {
+			inside = true
+		}
+	}
+
+	if _, x, _ := splitWsTokens(tokens); len(x) == 0 {
+		return nil, nil
+	}
+	var body []genericinterperter.Tokener
+	exprs, commas := splitTokens(tokens, glanglexer.CommaToken)
+	for i, expr := range exprs {
+		lead, expr, trail := splitWsTokens(expr)
+		if len(expr) == 0 {
+			return nil, I.DebugAtToken(nearToken(commas, i, block.Close), "empty expression in the implements")
+		}
+		stages, pipes := splitTokens(expr, glanglexer.OrToken)
+
+		chain := glang.NewImplementChain()
+		for k, s := range stages {
+			slead, s, strail := splitWsTokens(s)
+			if len(s) == 0 {
+				return nil, I.DebugAtToken(nearToken(pipes, k, block.Close), "empty stage in the implements pipeline")
+			}
+			chain.AddExprs(slead)
+			if k == 0 {
+				// the source is a single expression.
+				source := glang.NewExpressionDecl()
+				source.AddExprs(s)
+				chain.Source = source
+				chain.AddExpr(source)
+			} else {
+				stage, err := I.readChainStage(s)
+				if err != nil {
+					return nil, err
+				}
+				chain.Stages = append(chain.Stages, stage)
+				chain.AddExpr(stage)
+			}
+			chain.AddExprs(strail)
+			if k < len(pipes) {
+				chain.AddExpr(pipes[k])
+			}
+		}
+		body = append(body, lead...)
+		body = append(body, chain)
+		body = append(body, trail...)
+		if i < len(commas) {
+			body = append(body, commas[i])
+		}
+		ret = append(ret, chain)
+	}
+
+	var rebuilt []genericinterperter.Tokener
+	inside = false
+	for _, t := range block.Tokens {
+		if t == block.Close {
+			rebuilt = append(rebuilt, body...)
+			inside = false
+		}
+		if !inside {
+			rebuilt = append(rebuilt, t)
+		}
+		if t == block.Open {
+			inside = true
+		}
+	}
+	block.Tokens = rebuilt
+
+	return ret, nil
+}
+
+// readChainStage reads the tokens of a stage of an implements pipeline,
+// a template name followed by its args separated by spaces out of parens,
+// Slice "Name" (quote .Name).
+func (I *GigoInterpreter) readChainStage(s []genericinterperter.Tokener) (*glang.ChainStage, error) {
+	if s[0].GetType() != genericlexer.WordToken {
+		return nil, I.DebugAtToken(s[0], "unexpected token in the implements pipeline", genericlexer.WordToken)
+	}
+	ret := glang.NewChainStage()
+
+	// the name of a library template is tpl.Name.
+	n := 1
+	if len(s) > 2 && s[1].GetType() == glanglexer.DotToken && s[2].GetType() == genericlexer.WordToken {
+		n = 3
+	}
+	ret.Template = glang.NewIdentifierDecl()
+	ret.Template.AddExprs(s[:n])
+	ret.AddExpr(ret.Template)
+
+	arg := glang.NewExpressionDecl()
+	flush := func() {
+		if len(arg.GetTokens()) > 0 {
+			ret.Args = append(ret.Args, arg)
+			ret.AddExpr(arg)
+			arg = glang.NewExpressionDecl()
+		}
+	}
+	depth := 0
+	for _, t := range s[n:] {
+		switch t.GetType() {
+		case glanglexer.ParenOpenToken:
+			depth++
+		case glanglexer.ParenCloseToken:
+			depth--
+		case genericlexer.WsToken:
+			if depth == 0 {
+				flush()
+				ret.AddExpr(t)
+				continue
+			}
+		}
+		arg.AddExpr(t)
+	}
+	flush()
+	return ret, nil
+}
+
+// splitTokens splits s at its tokens of type T out of parens,
+// it returns the parts and the separators.
+func splitTokens(s []genericinterperter.Tokener, T lexer.TokenType) ([][]genericinterperter.Tokener, []genericinterperter.Tokener) {
+	parts := [][]genericinterperter.Tokener{{}}
+	var seps []genericinterperter.Tokener
+	depth := 0
+	for _, t := range s {
+		switch t.GetType() {
+		case glanglexer.ParenOpenToken:
+			depth++
+		case glanglexer.ParenCloseToken:
+			depth--
+		case T:
+			if depth == 0 {
+				parts = append(parts, []genericinterperter.Tokener{})
+				seps = append(seps, t)
+				continue
+			}
+		}
+		parts[len(parts)-1] = append(parts[len(parts)-1], t)
+	}
+	return parts, seps
+}
+
+// nearToken returns the separator after the part i, or the one before,
+// or def.
+func nearToken(seps []genericinterperter.Tokener, i int, def genericinterperter.Tokener) genericinterperter.Tokener {
+	if i < len(seps) {
+		return seps[i]
+	}
+	if i > 0 {
+		return seps[i-1]
+	}
+	return def
+}
+
+// splitWsTokens returns the leading whitespaces of s,
+// the tokens in between, and the trailing whitespaces.
+func splitWsTokens(s []genericinterperter.Tokener) ([]genericinterperter.Tokener, []genericinterperter.Tokener, []genericinterperter.Tokener) {
+	i := 0
+	for i < len(s) && s[i].GetType() == genericlexer.WsToken {
+		i++
+	}
+	j := len(s)
+	for j > i && s[j-1].GetType() == genericlexer.WsToken {
+		j--
+	}
+	return s[:i], s[i:j], s[j:]
+}
+
 // ReadInterfaceDecl reads an interface with its signs.
 // the next token must be a InterfaceToken
 // returns an error if none is found.
 // type xx interface { block }
-func (I *GigoInterpreter) ReadInterfaceDecl() (*glang.InterfaceDecl, error) {
+func (I *GigoInterpreter) ReadInterfaceDecl(templated bool) (*glang.InterfaceDecl, error) {
 
 	intfTok := I.Read(glanglexer.InterfaceToken)
 	if intfTok == nil {
@@ -2391,7 +3545,7 @@ func (I *GigoInterpreter) ReadInterfaceDecl() (*glang.InterfaceDecl, error) {
 	ret := glang.NewInterfaceDecl()
 	ret.AddExprs(I.Emit())
 
-	block, err := I.ReadSignsBlock(glanglexer.BraceOpenToken, glanglexer.BraceCloseToken)
+	block, err := I.ReadSignsBlock(templated, glanglexer.BraceOpenToken, glanglexer.BraceCloseToken)
 	if err != nil {
 		return nil, err
 	}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 
 	genericinterperter "github.com/mh-cbon/gigo/interpreter/generic"
@@ -832,6 +833,466 @@ case "rr":
 	// Dump(block)
 }
 
+func TestReadTypeSwitchStmt(t *testing.T) {
+	content := `switch x.(type) {}
+switch v := x.(type) {
+case int, *pkg.T:
+	fmt.Println(v)
+case nil:
+default:
+}
+switch zz {}
+`
+	interpret := makeRawInterpreter(content)
+
+	if !interpret.isTypeSwitch() {
+		t.Errorf("unexpected isTypeSwitch wanted=%v, got=%v", true, false)
+	}
+	block, err := interpret.ReadTypeSwitchStmt(false)
+	mustNotErr(t, err)
+	bodyEq(t, block, "{}")
+	StringEq(t, block.Assert, "x.(type)")
+	StringEq(t, block.Assert.X, "x")
+	mustNil(t, block.Init)
+	interpret.GetMany(glanglexer.NlToken)
+
+	block, err = interpret.ReadTypeSwitchStmt(false)
+	mustNotErr(t, err)
+	initEq(t, block, "v := x.(type) ")
+	StringEq(t, block.Assert, "x.(type)")
+	identifierNameEq(t, block.Init.IDs[0], "v")
+	branchEq(t, block, 0, "case int, *pkg.T:\n\tfmt.Println(v)\n")
+	branchEq(t, block, 1, "case nil:\n")
+	branchEq(t, block, 2, "default:\n")
+	StringEq(t, block.GetBranch(0).Cond, " int, *pkg.T")
+	swanted := "v"
+	sgot := block.GetVarName()
+	if swanted != sgot {
+		t.Errorf("unexpected var name wanted=%q, got=%q", swanted, sgot)
+	}
+	interpret.GetMany(glanglexer.NlToken)
+
+	if interpret.isTypeSwitch() {
+		t.Errorf("unexpected isTypeSwitch wanted=%v, got=%v", false, true)
+	}
+	_, err = interpret.ReadTypeSwitchStmt(false)
+	mustErr(t, err)
+}
+
+func TestReadTypeAssertExpr(t *testing.T) {
+	content := `y := x.(int)
+z, ok := x.(*pkg.T)
+n := x.(fmt.Stringer).String()
+`
+	interpret := makeRawInterpreter(content)
+
+	block, err := interpret.ReadAssignExpr(false, true, glanglexer.SemiColonToken)
+	mustNotErr(t, err)
+	StringEq(t, block.Values[0], "x.(int)")
+	assert := glang.FindTypeAssert(block)
+	mustNotNil(t, assert)
+	StringEq(t, assert.X, "x")
+	StringEq(t, assert.Type, "int")
+	if assert.IsTypeSwitch() {
+		t.Errorf("unexpected IsTypeSwitch wanted=%v, got=%v", false, true)
+	}
+	interpret.GetMany(glanglexer.NlToken)
+
+	block, err = interpret.ReadAssignExpr(false, true, glanglexer.SemiColonToken)
+	mustNotErr(t, err)
+	lenEq(t, 2, len(block.IDs))
+	StringEq(t, block.Values[0], "x.(*pkg.T)")
+	StringEq(t, glang.FindTypeAssert(block).Type, "*pkg.T")
+	interpret.GetMany(glanglexer.NlToken)
+
+	block, err = interpret.ReadAssignExpr(false, true, glanglexer.SemiColonToken)
+	mustNotErr(t, err)
+	StringEq(t, block.Values[0], "x.(fmt.Stringer).String()")
+	StringEq(t, glang.FindTypeAssert(block).Type, "fmt.Stringer")
+	interpret.GetMany(glanglexer.NlToken)
+
+	content = `s[0].(T)
+g().(int)
+m["k"].(string)
+s[i].Item.(*T).Name
+`
+	interpret = makeRawInterpreter(content)
+
+	expr, err := interpret.ReadExpressionBlock(false)
+	mustNotErr(t, err)
+	assert, ok := expr.GetExprs()[0].(*glang.TypeAssertExpr)
+	if !ok {
+		t.Fatalf("unexpected expr wanted=%q, got=%T", "*glang.TypeAssertExpr", expr.GetExprs()[0])
+	}
+	StringEq(t, assert, "s[0].(T)")
+	if _, ok := assert.X.(*glang.IndexExpr); !ok {
+		t.Errorf("unexpected X wanted=%q, got=%T", "*glang.IndexExpr", assert.X)
+	}
+	StringEq(t, assert.X, "s[0]")
+	StringEq(t, assert.Type, "T")
+	interpret.GetMany(glanglexer.NlToken)
+
+	expr, err = interpret.ReadExpressionBlock(false)
+	mustNotErr(t, err)
+	assert = expr.GetExprs()[0].(*glang.TypeAssertExpr)
+	if _, ok := assert.X.(*glang.CallExpr); !ok {
+		t.Errorf("unexpected X wanted=%q, got=%T", "*glang.CallExpr", assert.X)
+	}
+	StringEq(t, assert.X, "g()")
+	StringEq(t, assert.Type, "int")
+	interpret.GetMany(glanglexer.NlToken)
+
+	expr, err = interpret.ReadExpressionBlock(false)
+	mustNotErr(t, err)
+	assert = expr.GetExprs()[0].(*glang.TypeAssertExpr)
+	StringEq(t, assert.X, `m["k"]`)
+	StringEq(t, assert.Type, "string")
+	interpret.GetMany(glanglexer.NlToken)
+
+	expr, err = interpret.ReadExpressionBlock(false)
+	mustNotErr(t, err)
+	StringEq(t, expr, "s[i].Item.(*T).Name")
+	assert = glang.FindTypeAssert(expr)
+	StringEq(t, assert.X, "s[i].Item")
+	StringEq(t, assert.Type, "*T")
+	interpret.GetMany(glanglexer.NlToken)
+}
+
+func TestReadConversionExpr(t *testing.T) {
+	str := `func (i Item) Show() (string, error) {
+	fmt.Println(string(i.B))
+	f(int(x))
+	y := g().(int)
+	z := m["k"].(string)
+	return string(b), nil
+}`
+	d, err := interpretString("tomate", str)
+	mustNotErr(t, err)
+	StringEq(t, d, str)
+
+	calls := []string{}
+	genericinterperter.Inspect(d, func(n genericinterperter.Expressioner) bool {
+		if c, ok := n.(*glang.CallExpr); ok {
+			calls = append(calls, c.ID.GetSlugName())
+		}
+		return true
+	})
+	wanted := []string{"fmt.Println", "string", "f", "int", "g", "string"}
+	if strings.Join(calls, ",") != strings.Join(wanted, ",") {
+		t.Errorf("unexpected calls wanted=%q, got=%q", wanted, calls)
+	}
+
+	content := `f(])
+`
+	interpret := makeRawInterpreter(content)
+	_, err = interpret.ReadExpressionBlock(false)
+	mustErr(t, err)
+}
+
+func TestReadStructLit(t *testing.T) {
+	str := `func tomate() {
+	x := struct{A int}{A: 1}
+	done <- struct{}{}
+}`
+	d, err := interpretString("tomate", str)
+	mustNotErr(t, err)
+	StringEq(t, d, str)
+
+	lits := []*glang.CompositeLit{}
+	genericinterperter.Inspect(d, func(n genericinterperter.Expressioner) bool {
+		if _, ok := n.(*glang.LabeledStmt); ok {
+			t.Errorf("unexpected labeled stmt %q", n)
+		}
+		if x, ok := n.(*glang.CompositeLit); ok {
+			lits = append(lits, x)
+		}
+		return true
+	})
+	lenEq(t, 2, len(lits))
+	if _, ok := lits[0].Type.(*glang.StructDecl); !ok {
+		t.Errorf("unexpected type wanted=%q, got=%T", "*glang.StructDecl", lits[0].Type)
+	}
+	StringEq(t, lits[0].Type, "struct{A int}")
+	lenEq(t, 1, len(lits[0].Elts))
+	kv, ok := lits[0].GetElt(0).(*glang.KeyValueExpr)
+	if !ok {
+		t.Fatalf("unexpected element wanted=%q, got=%T", "*glang.KeyValueExpr", lits[0].GetElt(0))
+	}
+	StringEq(t, kv.Key, "A")
+	StringEq(t, kv.Value, "1")
+	StringEq(t, lits[1], "struct{}{}")
+	lenEq(t, 0, len(lits[1].Elts))
+}
+
+func TestReadLabeledStmt(t *testing.T) {
+	str := `func tomate() {
+outer:
+	for {
+		break outer
+	}
+	goto outer
+	for {
+		continue
+	}
+}`
+	d, err := interpretString("tomate", str)
+	mustNotErr(t, err)
+	StringEq(t, d, str)
+
+	body := d.FindFuncs()[0].GetBody()
+	labels := []*glang.LabeledStmt{}
+	jumps := []*glang.JumpStmt{}
+	for _, e := range body.GetExprs() {
+		if x, ok := e.(*glang.LabeledStmt); ok {
+			labels = append(labels, x)
+		} else if x, ok := e.(*glang.JumpStmt); ok {
+			jumps = append(jumps, x)
+		}
+	}
+	lenEq(t, 1, len(labels))
+	lenEq(t, 1, len(jumps))
+
+	swanted := "outer"
+	sgot := labels[0].GetLabel()
+	if swanted != sgot {
+		t.Errorf("unexpected label wanted=%q, got=%q", swanted, sgot)
+	}
+	if _, ok := labels[0].Stmt.(*glang.ForStmt); !ok {
+		t.Errorf("unexpected labeled stmt wanted=%v, got=%T", "*glang.ForStmt", labels[0].Stmt)
+	}
+	StringEq(t, jumps[0], "goto outer")
+	swanted = "goto outer"
+	sgot = jumps[0].GetKeyword() + " " + jumps[0].GetLabel()
+	if swanted != sgot {
+		t.Errorf("unexpected jump wanted=%q, got=%q", swanted, sgot)
+	}
+
+	content := `break outer
+continue
+goto
+`
+	interpret := makeRawInterpreter(content)
+	jump, err := interpret.ReadJumpStmt(false)
+	mustNotErr(t, err)
+	StringEq(t, jump.Label, "outer")
+	interpret.GetMany(glanglexer.NlToken)
+
+	jump, err = interpret.ReadJumpStmt(false)
+	mustNotErr(t, err)
+	mustNil(t, jump.Label)
+	interpret.GetMany(glanglexer.NlToken)
+
+	_, err = interpret.ReadJumpStmt(false)
+	mustErr(t, err)
+}
+
+func TestReadFuncLit(t *testing.T) {
+	content := `f := func(a int) int {
+	return a
+}
+go func() {}()
+`
+	interpret := makeRawInterpreter(content)
+
+	block, err := interpret.ReadAssignExpr(false, true, glanglexer.SemiColonToken)
+	mustNotErr(t, err)
+	var lit *glang.FuncLit
+	for _, e := range block.Values[0].GetExprs() {
+		if x, ok := e.(*glang.FuncLit); ok {
+			lit = x
+		}
+	}
+	mustNotNil(t, lit)
+	StringEq(t, lit, "func(a int) int {\n\treturn a\n}")
+	StringEq(t, lit.GetArgs(), "(a int)")
+	StringEq(t, lit.GetOut(), " int")
+	interpret.GetMany(glanglexer.NlToken)
+
+	stmt, err := interpret.readStmt(false)
+	mustNotErr(t, err)
+	StringEq(t, stmt, "go func() {}()")
+}
+
+func TestReadCompositeLit(t *testing.T) {
+	content := `Todo{Name: "a", Done: true}
+[]int{1, 2}
+map[string][]*T{"a": {}, "b": nil}
+[]T{{a: 1},
+	{a: 2},
+}
+`
+	interpret := makeRawInterpreter(content)
+
+	block, err := interpret.ReadExpressionBlock(false)
+	mustNotErr(t, err)
+	StringEq(t, block, `Todo{Name: "a", Done: true}`)
+	lit, ok := block.GetExprs()[0].(*glang.CompositeLit)
+	if !ok {
+		t.Fatalf("unexpected expr wanted=%q, got=%T", "*glang.CompositeLit", block.GetExprs()[0])
+	}
+	StringEq(t, lit.Type, "Todo")
+	lenEq(t, 2, len(lit.Elts))
+	kv, ok := lit.GetElt(1).(*glang.KeyValueExpr)
+	if !ok {
+		t.Fatalf("unexpected element wanted=%q, got=%T", "*glang.KeyValueExpr", lit.GetElt(1))
+	}
+	StringEq(t, kv.Key, "Done")
+	StringEq(t, kv.Value, "true")
+	interpret.GetMany(glanglexer.NlToken)
+
+	block, err = interpret.ReadExpressionBlock(false)
+	mustNotErr(t, err)
+	lit = block.GetExprs()[0].(*glang.CompositeLit)
+	StringEq(t, lit.Type, "[]int")
+	lenEq(t, 2, len(lit.Elts))
+	StringEq(t, lit.GetElt(0), "1")
+	StringEq(t, lit.GetElt(1), "2")
+	interpret.GetMany(glanglexer.NlToken)
+
+	block, err = interpret.ReadExpressionBlock(false)
+	mustNotErr(t, err)
+	lit = block.GetExprs()[0].(*glang.CompositeLit)
+	StringEq(t, lit.Type, "map[string][]*T")
+	lenEq(t, 2, len(lit.Elts))
+	kv = lit.GetElt(0).(*glang.KeyValueExpr)
+	StringEq(t, kv.Key, `"a"`)
+	elided := kv.Value.(*glang.CompositeLit)
+	mustNil(t, elided.Type)
+	lenEq(t, 0, len(elided.Elts))
+	interpret.GetMany(glanglexer.NlToken)
+
+	block, err = interpret.ReadExpressionBlock(false)
+	mustNotErr(t, err)
+	StringEq(t, block, "[]T{{a: 1},\n\t{a: 2},\n}")
+	lit = block.GetExprs()[0].(*glang.CompositeLit)
+	lenEq(t, 2, len(lit.Elts))
+	StringEq(t, lit.GetElt(1), "{a: 2}")
+}
+
+func TestReadIndexSliceExpr(t *testing.T) {
+	content := `m[k]
+s.items[:i]
+s[i+1:j:cap(s)]
+s[i][0].Name
+`
+	interpret := makeRawInterpreter(content)
+
+	block, err := interpret.ReadExpressionBlock(false)
+	mustNotErr(t, err)
+	index, ok := block.GetExprs()[0].(*glang.IndexExpr)
+	if !ok {
+		t.Fatalf("unexpected expr wanted=%q, got=%T", "*glang.IndexExpr", block.GetExprs()[0])
+	}
+	StringEq(t, index.X, "m")
+	StringEq(t, index.Index, "k")
+	interpret.GetMany(glanglexer.NlToken)
+
+	block, err = interpret.ReadExpressionBlock(false)
+	mustNotErr(t, err)
+	slice, ok := block.GetExprs()[0].(*glang.SliceExpr)
+	if !ok {
+		t.Fatalf("unexpected expr wanted=%q, got=%T", "*glang.SliceExpr", block.GetExprs()[0])
+	}
+	StringEq(t, slice, "s.items[:i]")
+	StringEq(t, slice.X, "s.items")
+	mustNil(t, slice.Low)
+	StringEq(t, slice.High, "i")
+	mustNil(t, slice.Max)
+	if slice.Slice3 {
+		t.Errorf("unexpected Slice3 wanted=%v, got=%v", false, slice.Slice3)
+	}
+	interpret.GetMany(glanglexer.NlToken)
+
+	block, err = interpret.ReadExpressionBlock(false)
+	mustNotErr(t, err)
+	slice = block.GetExprs()[0].(*glang.SliceExpr)
+	StringEq(t, slice.Low, "i+1")
+	StringEq(t, slice.High, "j")
+	StringEq(t, slice.Max, "cap(s)")
+	if !slice.Slice3 {
+		t.Errorf("unexpected Slice3 wanted=%v, got=%v", true, slice.Slice3)
+	}
+	interpret.GetMany(glanglexer.NlToken)
+
+	block, err = interpret.ReadExpressionBlock(false)
+	mustNotErr(t, err)
+	StringEq(t, block, "s[i][0].Name")
+	sel := block.GetExprs()[0].(*glang.ExpressionDecl)
+	index = sel.GetExprs()[0].(*glang.IndexExpr)
+	StringEq(t, index.Index, "0")
+	StringEq(t, index.X.(*glang.IndexExpr).X, "s")
+	interpret.GetMany(glanglexer.NlToken)
+
+	content = `s[]
+`
+	interpret = makeRawInterpreter(content)
+	_, err = interpret.ReadExpressionBlock(false)
+	mustErr(t, err)
+}
+
+func TestReadUnaryExpr(t *testing.T) {
+	content := `&Todos{}
+*p = -x * 2
+!ok
+<-done
+done <- true
+`
+	interpret := makeRawInterpreter(content)
+
+	block, err := interpret.ReadExpressionBlock(false)
+	mustNotErr(t, err)
+	unary, ok := block.GetExprs()[0].(*glang.UnaryExpr)
+	if !ok {
+		t.Fatalf("unexpected expr wanted=%q, got=%T", "*glang.UnaryExpr", block.GetExprs()[0])
+	}
+	if !unary.IsAddr() {
+		t.Errorf("unexpected op wanted=%q, got=%q", "&", unary.GetOp())
+	}
+	lit := unary.X.(*glang.CompositeLit)
+	StringEq(t, lit.Type, "Todos")
+	interpret.GetMany(glanglexer.NlToken)
+
+	block, err = interpret.ReadExpressionBlock(false)
+	mustNotErr(t, err)
+	StringEq(t, block, "*p = -x * 2")
+	unaries := []*glang.UnaryExpr{}
+	for _, e := range block.GetExprs() {
+		if x, ok := e.(*glang.UnaryExpr); ok {
+			unaries = append(unaries, x)
+		}
+	}
+	lenEq(t, 2, len(unaries))
+	if !unaries[0].IsDeref() {
+		t.Errorf("unexpected op wanted=%q, got=%q", "*", unaries[0].GetOp())
+	}
+	StringEq(t, unaries[0].X, "p")
+	StringEq(t, unaries[1].Op, "-")
+	StringEq(t, unaries[1].X, "x")
+	interpret.GetMany(glanglexer.NlToken)
+
+	block, err = interpret.ReadExpressionBlock(false)
+	mustNotErr(t, err)
+	unary = block.GetExprs()[0].(*glang.UnaryExpr)
+	StringEq(t, unary.Op, "!")
+	interpret.GetMany(glanglexer.NlToken)
+
+	block, err = interpret.ReadExpressionBlock(false)
+	mustNotErr(t, err)
+	unary = block.GetExprs()[0].(*glang.UnaryExpr)
+	StringEq(t, unary.Op, "<-")
+	StringEq(t, unary.X, "done")
+	interpret.GetMany(glanglexer.NlToken)
+
+	block, err = interpret.ReadExpressionBlock(false)
+	mustNotErr(t, err)
+	StringEq(t, block, "done <- true")
+	for _, e := range block.GetExprs() {
+		if x, ok := e.(*glang.UnaryExpr); ok {
+			t.Errorf("unexpected unary expr %q", x)
+		}
+	}
+}
+
 func TestAssignExpr(t *testing.T) {
 	content := `x := "r"
 y := 5
@@ -1685,6 +2146,525 @@ func TestOneStructWithProps(t *testing.T) {
 	// Dump(d, 0)
 }
 
+func TestOneStructWithTags(t *testing.T) {
+
+	str := "type tomate struct {\n" +
+		"\tA string `json:\"a,omitempty\" db:\"col_a\"`\n" +
+		"\tB struct {\n" +
+		"\t\tC int `json:\"c\"`\n" +
+		"\t} `json:\"b\"`\n" +
+		"\t*Embed `json:\"-\"`\n" +
+		"\tD bool\n" +
+		"}"
+	d, err := interpretString("tomate", str)
+	if err != nil {
+		t.Errorf("%#v\n", err)
+		t.Errorf("%+v\n", err)
+		return
+	}
+
+	st := d.FindStructsTypes()[0]
+	StringEq(t, st, str)
+
+	props := st.Block.Props
+	lenEq(t, 3, len(props))
+
+	propA := props[0]
+	identifierNameEq(t, propA.Name, "A")
+	StringEq(t, propA.Type, "string")
+	StringEq(t, propA.Tag, "`json:\"a,omitempty\" db:\"col_a\"`")
+	sgot := propA.Tag.Lookup("db")
+	swanted := "col_a"
+	if swanted != sgot {
+		t.Errorf("unexpected propA tag wanted=%q, got=%q", swanted, sgot)
+	}
+	sgot = propA.Tag.Lookup("json")
+	swanted = "a,omitempty"
+	if swanted != sgot {
+		t.Errorf("unexpected propA tag wanted=%q, got=%q", swanted, sgot)
+	}
+	sgot = strings.Join(propA.Tag.Keys(), ",")
+	swanted = "json,db"
+	if swanted != sgot {
+		t.Errorf("unexpected propA tag keys wanted=%q, got=%q", swanted, sgot)
+	}
+
+	propB := props[1]
+	identifierNameEq(t, propB.Name, "B")
+	if propB.IsInlineStruct() == false {
+		t.Errorf("unexpected propB inline struct wanted=%v, got=%v", true, false)
+	}
+	inline := propB.GetStruct()
+	lenEq(t, 1, len(inline.Block.Props))
+	StringEq(t, inline.Block.Props[0].Tag, "`json:\"c\"`")
+	StringEq(t, propB.Tag, "`json:\"b\"`")
+
+	propD := props[2]
+	identifierNameEq(t, propD.Name, "D")
+	if propD.Tag != nil {
+		t.Errorf("unexpected propD tag wanted=%v, got=%v", nil, propD.Tag)
+	}
+	if propD.Tag.Lookup("json") != "" {
+		t.Errorf("unexpected propD tag lookup wanted=%q, got=%q", "", propD.Tag.Lookup("json"))
+	}
+
+	lenEq(t, 1, len(st.Block.Underlying))
+	StringEq(t, st.Block.GetUnderlyingTag(st.Block.Underlying[0]), "`json:\"-\"`")
+}
+
+func TestOneStructFields(t *testing.T) {
+
+	str := "type tomate struct {\n" +
+		"\tName string `json:\"name\"`\n" +
+		"\t*sync.Mutex\n" +
+		"\tdone bool\n" +
+		"\tItems []*pkg.Todo\n" +
+		"\tIndex map[string][]int\n" +
+		"\tOut chan<- int\n" +
+		"\tOnDone func() error\n" +
+		"\tID channelID\n" +
+		"\tF functor\n" +
+		"\tS structure\n" +
+		"\tI interfaces\n" +
+		"}"
+	d, err := interpretString("tomate", str)
+	if err != nil {
+		t.Errorf("%#v\n", err)
+		t.Errorf("%+v\n", err)
+		return
+	}
+
+	st := d.FindStructsTypes()[0]
+	fields := st.GetFields()
+	lenEq(t, 11, len(fields))
+
+	type fieldWanted struct {
+		name     string
+		typ      string
+		kind     string
+		embedded bool
+		exported bool
+	}
+	wanted := []fieldWanted{
+		{"Name", "string", "named", false, true},
+		{"Mutex", "*sync.Mutex", "pointer", true, true},
+		{"done", "bool", "named", false, false},
+		{"Items", "[]*pkg.Todo", "slice", false, true},
+		{"Index", "map[string][]int", "map", false, true},
+		{"Out", "chan<- int", "chan", false, true},
+		{"OnDone", "func() error", "func", false, true},
+		{"ID", "channelID", "named", false, true},
+		{"F", "functor", "named", false, true},
+		{"S", "structure", "named", false, true},
+		{"I", "interfaces", "named", false, true},
+	}
+	for i, w := range wanted {
+		f := fields[i]
+		got := fieldWanted{f.Name, f.Type.String(), f.Type.Kind(), f.Embedded, f.Exported}
+		if w != got {
+			t.Errorf("unexpected field %v wanted=%v, got=%v", i, w, got)
+		}
+	}
+
+	sgot := fields[0].Lookup("json")
+	swanted := "name"
+	if swanted != sgot {
+		t.Errorf("unexpected field tag wanted=%q, got=%q", swanted, sgot)
+	}
+	sgot = fields[3].Type.Base().Name()
+	swanted = "Todo"
+	if swanted != sgot {
+		t.Errorf("unexpected field base type wanted=%q, got=%q", swanted, sgot)
+	}
+	sgot = fields[3].Type.Base().Pkg()
+	swanted = "pkg"
+	if swanted != sgot {
+		t.Errorf("unexpected field base pkg wanted=%q, got=%q", swanted, sgot)
+	}
+	sgot = fields[4].Type.Key().String() + " " + fields[4].Type.Elem().String()
+	swanted = "string []int"
+	if swanted != sgot {
+		t.Errorf("unexpected field map types wanted=%q, got=%q", swanted, sgot)
+	}
+	sgot = fields[5].Type.Elem().String()
+	swanted = "int"
+	if swanted != sgot {
+		t.Errorf("unexpected field chan type wanted=%q, got=%q", swanted, sgot)
+	}
+	for typ, builtin := range map[string]bool{"float64": true, "float": false, "channelID": false} {
+		if got := glang.NewTypeRef(typ).IsBuiltin(); got != builtin {
+			t.Errorf("unexpected builtin %v wanted=%v, got=%v", typ, builtin, got)
+		}
+	}
+	if st.GetField("done") != fields[2] && st.GetField("done").Name != "done" {
+		t.Errorf("unexpected field lookup wanted=%q, got=%v", "done", st.GetField("done"))
+	}
+	if st.GetField("nop") != nil {
+		t.Errorf("unexpected field lookup wanted=%v, got=%v", nil, st.GetField("nop"))
+	}
+}
+
+func TestInspectCallExprs(t *testing.T) {
+
+	str := `func (s *Todos) Push(t Todo) {
+	if t.Valid() {
+		s.items = append(s.items, t)
+	}
+}
+func Len() int { return 0 }
+`
+	d, err := interpretString("tomate", str)
+	if err != nil {
+		t.Errorf("%#v\n", err)
+		t.Errorf("%+v\n", err)
+		return
+	}
+
+	calls := []string{}
+	genericinterperter.InspectWithParents(d, func(n genericinterperter.Expressioner, parents []genericinterperter.Expressioner) bool {
+		c, ok := n.(*glang.CallExpr)
+		if !ok {
+			return true
+		}
+		for _, p := range parents {
+			if f, ok := p.(*glang.FuncDecl); ok && f.IsMethod() {
+				calls = append(calls, c.ID.GetSlugName())
+			}
+		}
+		return true
+	})
+	wanted := []string{"t.Valid", "append"}
+	if strings.Join(calls, ",") != strings.Join(wanted, ",") {
+		t.Errorf("unexpected calls wanted=%q, got=%q", wanted, calls)
+	}
+
+	parents := genericinterperter.NewParents(d)
+	funcs := d.FindFuncs()
+	lenEq(t, 2, len(funcs))
+	if parents.Parent(funcs[0]) != d {
+		t.Errorf("unexpected parent wanted=%T, got=%T", d, parents.Parent(funcs[0]))
+	}
+}
+
+func TestCloneFile(t *testing.T) {
+
+	str := `package tomate
+
+// Todos is a list.
+type Todos struct {
+	items []Todo
+}
+
+func (s *Todos) Push(t Todo) {
+	if len(s.items) > 0 {
+		s.items = append(s.items, t)
+	}
+}
+
+template <:.Name>Slice struct {
+	<:.Name>
+}
+`
+	d, err := interpretString("tomate", str)
+	if err != nil {
+		t.Errorf("%#v\n", err)
+		t.Errorf("%+v\n", err)
+		return
+	}
+	c := d.Clone()
+	StringEq(t, c, d.String())
+
+	nodes := map[genericinterperter.Expressioner]bool{}
+	genericinterperter.Inspect(d, func(n genericinterperter.Expressioner) bool {
+		if n != nil {
+			nodes[n] = true
+		}
+		return true
+	})
+	genericinterperter.Inspect(c, func(n genericinterperter.Expressioner) bool {
+		if n != nil && nodes[n] {
+			t.Errorf("unexpected node shared with the original %T %q", n, n)
+		}
+		return true
+	})
+
+	// fields and tokens still point to the same nodes.
+	f := c.FindFuncs()[0]
+	if f.Tokens[f.GetExprIndex(f.Name)] != f.Name {
+		t.Errorf("unexpected name of func, not found in its tokens")
+	}
+	StringEq(t, f.Receiver, "(s *Todos)")
+
+	c.SetTokenValue(glanglexer.TplOpenToken, "{{")
+	tpl := c.FindTemplatesTypes()[0]
+	StringEq(t, tpl.Name, "{{.Name>Slice")
+	StringEq(t, d.FindTemplatesTypes()[0].Name, "<:.Name>Slice")
+}
+
+func TestNodeSpan(t *testing.T) {
+
+	str := `package tomate
+
+func (s *Todos) Len() int {
+	return len(s.items) // ünïcode
+}
+`
+	d, err := interpretString("tomate", str)
+	if err != nil {
+		t.Errorf("%#v\n", err)
+		t.Errorf("%+v\n", err)
+		return
+	}
+	f := d.FindFuncs()[0]
+	if got := f.GetSpan().String(); got != "3:1-5:2" {
+		t.Errorf("unexpected span wanted=%q, got=%q", "3:1-5:2", got)
+	}
+	if got := f.Name.GetSpan().String(); got != "3:17-3:20" {
+		t.Errorf("unexpected span wanted=%q, got=%q", "3:17-3:20", got)
+	}
+	body := f.Body.GetSpan()
+	if !body.Contains(genericinterperter.Position{Line: 4, Col: 30}) {
+		t.Errorf("unexpected span %v does not contain 4:30", body)
+	}
+	if got := body.End.Offset; got != len(str)-1 {
+		t.Errorf("unexpected offset wanted=%v, got=%v", len(str)-1, got)
+	}
+}
+
+func TestSelect(t *testing.T) {
+
+	str := `package tomate
+
+var total = 1
+
+const max = 2
+
+type Todo struct {
+	Name string
+	Done bool
+}
+
+type Todos struct {
+	items []Todo
+}
+
+func (s *Todos) Push(t Todo) {}
+func (s Todos) Remove(t Todo) {}
+func (s Todos) RemoveAt(i int) {}
+func Push() {}
+
+template <:.Name>Slice struct {
+	items []<:.Name>
+}
+
+func (s <:.Name>Slice) Remove(t <:.Name>) {}
+
+type Pusher interface {
+	Push(t Todo)
+}
+`
+	d, err := interpretString("tomate", str)
+	if err != nil {
+		t.Errorf("%#v\n", err)
+		t.Errorf("%+v\n", err)
+		return
+	}
+
+	type selectWanted struct {
+		selector string
+		found    []string
+	}
+	wanted := []selectWanted{
+		{"Push", []string{"Push", "Push"}},
+		{"Todos.Push", []string{"Push"}},
+		{"func:*s.Remove*", []string{"Remove", "RemoveAt"}},
+		{"func:*Slice.Remove*", []string{"Remove"}},
+		{"template:*Slice", []string{"Slice"}},
+		{"template:Slice/func:Remove", []string{"Remove"}},
+		{"struct:Todo*", []string{"Todo", "Todos"}},
+		{"struct:Todo/field:Name", []string{"Name"}},
+		{"Todo/field:*", []string{"Name", "Done"}},
+		{"struct:Todos/func:*", []string{"Push", "Remove", "RemoveAt"}},
+		{"interface:Pusher/Push", []string{"Push"}},
+		{"var:total", []string{"total"}},
+		{"const:total", []string{}},
+		{"const:max", []string{"max"}},
+		{"Nop", []string{}},
+	}
+	for _, w := range wanted {
+		found, err := d.Select(w.selector)
+		mustNotErr(t, err)
+		got := []string{}
+		for _, f := range found {
+			switch x := f.(type) {
+			case *glang.AssignDecl:
+				got = append(got, x.GetLeft())
+			case slugNamer:
+				got = append(got, strings.TrimSpace(x.GetSlugName()))
+			case interface{ GetName() string }:
+				got = append(got, strings.TrimSpace(x.GetName()))
+			}
+		}
+		if strings.Join(got, ",") != strings.Join(w.found, ",") {
+			t.Errorf("unexpected selection of %q wanted=%q, got=%q", w.selector, w.found, got)
+		}
+	}
+
+	_, err = d.Select("struct:[")
+	mustErr(t, err)
+	_, err = d.Select("Todos/")
+	mustErr(t, err)
+}
+
+type slugNamer interface {
+	GetSlugName() string
+}
+
+func TestFindImports(t *testing.T) {
+
+	str := `package tomate
+
+import "fmt"
+
+import (
+	m "github.com/mh-cbon/gigo/ex/models"
+	_ "net/http/pprof"
+	"strings"
+)
+
+import s "sync"
+type Tomate struct{}
+`
+	d, err := interpretString("tomate", str)
+	if err != nil {
+		t.Errorf("%#v\n", err)
+		t.Errorf("%+v\n", err)
+		return
+	}
+
+	want := []glang.ImportSpec{
+		{Path: "fmt"},
+		{Name: "m", Path: "github.com/mh-cbon/gigo/ex/models"},
+		{Name: "_", Path: "net/http/pprof"},
+		{Path: "strings"},
+		{Name: "s", Path: "sync"},
+	}
+	got := d.FindImports()
+	if x := d.FindStructsTypes(); len(x) != 1 || x[0].GetName() != "Tomate" {
+		t.Errorf("unexpected structs %v", x)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("unexpected imports wanted=%v, got=%v", want, got)
+	}
+}
+
+func TestFindUses(t *testing.T) {
+
+	str := `package tomate
+
+//gigo:use tpl "github.com/mh-cbon/gigo/ex/tpls"
+//gigo:use "./tpls"
+// gigo:use is a directive
+//gigo:use nop
+
+//gigo:use b "example.com/b"
+type Todo struct {
+	Name string
+}
+`
+	d, err := interpretString("tomate", str)
+	if err != nil {
+		t.Errorf("%#v\n", err)
+		t.Errorf("%+v\n", err)
+		return
+	}
+
+	want := []glang.ImportSpec{
+		{Name: "tpl", Path: "github.com/mh-cbon/gigo/ex/tpls"},
+		{Path: "./tpls"},
+		{Name: "b", Path: "example.com/b"},
+	}
+	got := d.FindUses()
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("unexpected uses wanted=%v, got=%v", want, got)
+	}
+
+	// the comments kept by a declaration.
+	str = `package tomate
+
+//gigo:use "./a"
+//gigo:use "./b"
+type Todo struct {}
+`
+	d, err = interpretString("tomate", str)
+	mustNotErr(t, err)
+	want = []glang.ImportSpec{{Path: "./a"}, {Path: "./b"}}
+	got = d.FindUses()
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("unexpected uses wanted=%v, got=%v", want, got)
+	}
+}
+
+func TestOneFuncMethodView(t *testing.T) {
+
+	str := `func (s *Todos) Find(a, b int, opts ...string) (*Todo, error) {}
+func (s Todos) Len() (n int) {}
+func (s Todos) Each(fn func(Todo) bool) {}
+func (s *Todos) Set(m int, _ string, done bool) (_ int, embed error) {}
+`
+	d, err := interpretString("tomate", str)
+	if err != nil {
+		t.Errorf("%#v\n", err)
+		t.Errorf("%+v\n", err)
+		return
+	}
+	funcs := d.FindFuncs()
+	lenEq(t, 4, len(funcs))
+
+	type methodWanted struct {
+		params     string
+		callArgs   string
+		results    string
+		returnList string
+		hasError   bool
+		isPointer  bool
+		isVariadic bool
+	}
+	wanted := []methodWanted{
+		{"a int, b int, opts ...string", "a, b, opts...", "(res0 *Todo, res1 error)", "res0, res1", true, true, true},
+		{"", "", "(n int)", "n", false, false, false},
+		{"fn func(Todo) bool", "fn", "", "", false, false, false},
+		{"arg0 int, arg1 string, arg2 bool", "arg0, arg1, arg2", "(res0 int, res1 error)", "res0, res1", true, true, false},
+	}
+	for i, w := range wanted {
+		m := glang.NewMethodView(funcs[i])
+		got := methodWanted{
+			m.ParamsDecl(), m.CallArgs(), m.NamedResultsDecl(), m.ReturnList(),
+			m.HasError(), m.IsPointerReceiver(), m.IsVariadic(),
+		}
+		if w != got {
+			t.Errorf("unexpected method view %v wanted=%v, got=%v", i, w, got)
+		}
+	}
+
+	m := glang.NewMethodView(funcs[0])
+	swanted := "*Todo error"
+	sgot := strings.Join(m.ResultTypes(), " ")
+	if swanted != sgot {
+		t.Errorf("unexpected result types wanted=%q, got=%q", swanted, sgot)
+	}
+	swanted = "(*Todo, error)"
+	sgot = m.ResultsDecl()
+	if swanted != sgot {
+		t.Errorf("unexpected results decl wanted=%q, got=%q", swanted, sgot)
+	}
+	swanted = "Todos"
+	sgot = m.ReceiverType().Base().Name()
+	if swanted != sgot {
+		t.Errorf("unexpected receiver type wanted=%q, got=%q", swanted, sgot)
+	}
+}
+
 func TestOneTemplate(t *testing.T) {
 
 	str := `template Mutexed<:.Name> struct {
@@ -1792,6 +2772,15 @@ func TestOneInterface(t *testing.T) {
 		t.Errorf("unexpected sign2 name wanted=%q, got=%q", swanted, sgot)
 	}
 	// tbd params.
+
+	sgot = strings.TrimSpace(sign2.GetOut().String())
+	swanted = "int"
+	if swanted != sgot {
+		t.Errorf("unexpected sign2 out wanted=%q, got=%q", swanted, sgot)
+	}
+	if d.String() != str {
+		t.Errorf("unexpected output wanted=\n%q\ngot=\n%q", str, d.String())
+	}
 	// Dump(d, 0)
 }
 
@@ -2200,3 +3189,254 @@ func prettyPrinterLexer(reader genericinterperter.TokenerReader) genericinterper
 
 	return reader
 }
+
+func TestImplementChain(t *testing.T) {
+
+	str := `package tomate
+
+type Todos implements<:.Todo | Slice "Name" (quote .Name) | tpl.Mutexed> {}
+
+type Items implements<:Mutexed (Slice .Todo)> {}
+`
+	d, err := interpretString("tomate", str)
+	if err != nil {
+		t.Errorf("%#v\n", err)
+		t.Errorf("%+v\n", err)
+		return
+	}
+	impls := d.FindImplementsTypes()
+	if len(impls) != 2 {
+		t.Errorf("unexpected implements count wanted=%v, got=%v", 2, len(impls))
+		return
+	}
+	if len(impls[1].Chains) != 1 || len(impls[1].Chains[0].Stages) != 0 {
+		t.Errorf("unexpected chains %v", impls[1].Chains)
+	} else if want := "Mutexed (Slice .Todo)"; impls[1].Chains[0].Expr() != want {
+		t.Errorf("unexpected expr wanted=%q, got=%q", want, impls[1].Chains[0].Expr())
+	}
+	if len(impls[0].Chains) != 1 {
+		t.Errorf("unexpected chains %v", impls[0].Chains)
+		return
+	}
+	c := impls[0].Chains[0]
+	if want := `.Todo | Slice "Name" (quote .Name) | tpl.Mutexed`; c.String() != want {
+		t.Errorf("unexpected chain wanted=%q, got=%q", want, c.String())
+	}
+	if want := `tpl.Mutexed (Slice .Todo "Name" (quote .Name))`; c.Expr() != want {
+		t.Errorf("unexpected expr wanted=%q, got=%q", want, c.Expr())
+	}
+	if got := c.Stages[0].GetSpan().String(); got != "3:32-3:58" {
+		t.Errorf("unexpected span wanted=%v, got=%v", "3:32-3:58", got)
+	}
+	StringEq(t, c.Source, ".Todo")
+	StringEq(t, c.Stages[1].Template, "tpl.Mutexed")
+	if len(c.Stages[0].Args) != 2 {
+		t.Errorf("unexpected args %v", c.Stages[0].GetArgs())
+	} else {
+		StringEq(t, c.Stages[0].Args[1], "(quote .Name)")
+	}
+	StringEq(t, d, str)
+
+	// the chains and their stages are nodes of the tree.
+	found := map[genericinterperter.Expressioner]bool{}
+	genericinterperter.Inspect(d, func(n genericinterperter.Expressioner) bool {
+		found[n] = true
+		return true
+	})
+	if !found[c] || !found[c.Stages[0]] || !found[c.Stages[1].Template] {
+		t.Errorf("unexpected chain not found in the tree")
+	}
+
+	x := impls[0].Clone()
+	xc := x.Chains[0]
+	if xc == c || xc.Stages[0] == c.Stages[0] {
+		t.Errorf("unexpected chain shared with the original")
+	}
+	if x.ImplementTemplate.(*glang.BodyBlockDecl).GetExprIndex(xc) < 0 {
+		t.Errorf("unexpected chain of the clone, not found in its tokens")
+	}
+	StringEq(t, xc, c.String())
+
+	for _, str := range []string{
+		`package tomate
+
+type Todos implements<:.Todo | | Slice> {}
+`,
+		`package tomate
+
+type Todos implements<:.Todo | "Name"> {}
+`,
+	} {
+		if _, err := interpretString("tomate", str); err == nil {
+			t.Errorf("expected an error for %q", str)
+		}
+	}
+}
+
+func TestImplementChains(t *testing.T) {
+
+	str := `package tomate
+
+type Todos implements<:Mutexed .Todo, .Todo | Slice | Stringer, tpl.Dumper (Slice .Todo)> {}
+`
+	d, err := interpretString("tomate", str)
+	if err != nil {
+		t.Errorf("%#v\n", err)
+		t.Errorf("%+v\n", err)
+		return
+	}
+	impls := d.FindImplementsTypes()
+	if len(impls) != 1 {
+		t.Errorf("unexpected implements count wanted=%v, got=%v", 1, len(impls))
+		return
+	}
+	want := []string{"Mutexed .Todo", "Stringer (Slice .Todo)", "tpl.Dumper (Slice .Todo)"}
+	got := []string{}
+	for _, c := range impls[0].Chains {
+		got = append(got, c.Expr())
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("unexpected exprs wanted=%q, got=%q", want, got)
+	}
+
+	str = `package tomate
+
+type Todos implements<:Mutexed .Todo, , Stringer .Todo> {}
+`
+	if _, err := interpretString("tomate", str); err == nil {
+		t.Errorf("expected an error for %q", str)
+	}
+}
+
+func TestTemplateParams(t *testing.T) {
+
+	str := `package tomate
+
+template <:.Name>Slice(sorted bool, keys ...field) struct {
+  items []<:.Name>
+}
+
+template <:.Name>List struct {}
+`
+	d, err := interpretString("tomate", str)
+	if err != nil {
+		t.Errorf("%#v\n", err)
+		t.Errorf("%+v\n", err)
+		return
+	}
+	if d.String() != str {
+		t.Errorf("unexpected output wanted=\n%q\ngot=\n%q", str, d.String())
+	}
+	tpls := d.FindTemplatesTypes()
+	if len(tpls) != 2 {
+		t.Errorf("unexpected templates count wanted=%v, got=%v", 2, len(tpls))
+		return
+	}
+	if tpls[1].Params != nil {
+		t.Errorf("unexpected params %v", tpls[1].Params)
+	}
+	got := []string{}
+	for _, p := range tpls[0].GetParams() {
+		got = append(got, p.String())
+	}
+	if want := []string{"sorted bool", "keys ...field"}; !reflect.DeepEqual(want, got) {
+		t.Errorf("unexpected params wanted=%q, got=%q", want, got)
+	}
+
+	x := tpls[0].Clone()
+	x.RemoveParams()
+	if want := "template <:.Name>Slice struct {\n  items []<:.Name>\n}"; x.String() != want {
+		t.Errorf("unexpected template wanted=%q, got=%q", want, x.String())
+	}
+}
+
+func TestTemplatedMapKey(t *testing.T) {
+
+	str := `package tomate
+
+template <:.Name>Map struct {
+  items map[<:.T1>]<:.T2>
+  names map[string][]<:.Name>
+}
+`
+	d, err := interpretString("tomate", str)
+	if err != nil {
+		t.Errorf("%#v\n", err)
+		t.Errorf("%+v\n", err)
+		return
+	}
+	if d.String() != str {
+		t.Errorf("unexpected output wanted=\n%q\ngot=\n%q", str, d.String())
+	}
+	tpls := d.FindTemplatesTypes()
+	if len(tpls) != 1 {
+		t.Errorf("unexpected templates count wanted=%v, got=%v", 1, len(tpls))
+		return
+	}
+	// the template of the key is closed by a TplCloseToken, not a GreaterToken.
+	closes := 0
+	for _, p := range tpls[0].Block.Props {
+		closes += len(p.Type.FindAll(glanglexer.TplCloseToken))
+	}
+	if closes != 3 {
+		t.Errorf("unexpected TplCloseToken count wanted=%v, got=%v", 3, closes)
+	}
+}
+
+func TestTemplateExtras(t *testing.T) {
+
+	str := `package tomate
+
+template <:.Name>Slice struct {
+  items []<:.Name>
+}
+
+template(Slice) func New<:.Name>Slice() *<:.Name>Slice {
+  return &<:.Name>Slice{}
+}
+
+template( Slice ) const <:.Name>SliceMax = 10
+
+template(Slice) var <:.Name>SliceDefault = New<:.Name>Slice()
+
+template(Slice) type <:.Name>Iter struct {
+  i int
+}
+
+template(Slice) type <:.Name>Getter interface {
+  Get(i int) <:.Name>
+}
+`
+	d, err := interpretString("tomate", str)
+	if err != nil {
+		t.Errorf("%#v\n", err)
+		t.Errorf("%+v\n", err)
+		return
+	}
+	if d.String() != str {
+		t.Errorf("unexpected output wanted=\n%q\ngot=\n%q", str, d.String())
+	}
+	if tpls := d.FindTemplatesTypes(); len(tpls) != 1 {
+		t.Errorf("unexpected templates count wanted=%v, got=%v", 1, len(tpls))
+	}
+	extras := d.FindTemplateExtras()
+	want := []string{
+		"func New<:.Name>Slice() *<:.Name>Slice {\n  return &<:.Name>Slice{}\n}",
+		"const <:.Name>SliceMax = 10",
+		"var <:.Name>SliceDefault = New<:.Name>Slice()",
+		"type <:.Name>Iter struct {\n  i int\n}",
+		"type <:.Name>Getter interface {\n  Get(i int) <:.Name>\n}",
+	}
+	if len(extras) != len(want) {
+		t.Errorf("unexpected extras count wanted=%v, got=%v", len(want), len(extras))
+		return
+	}
+	for i, e := range extras {
+		if e.GetOf() != "Slice" {
+			t.Errorf("unexpected template of the extra %v wanted=%q, got=%q", i, "Slice", e.GetOf())
+		}
+		if got := strings.TrimSpace(e.Decl.String()); got != want[i] {
+			t.Errorf("unexpected extra %v wanted=%q, got=%q", i, want[i], got)
+		}
+	}
+}
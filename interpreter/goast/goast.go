@@ -0,0 +1,166 @@
+// Package goast converts between gigo declarations and the go/ast of the standard library.
+//
+// The conversion is limited to plain go, sources that go/parser accepts,
+// gigo syntax such as template or implements<> can not be exported.
+package goast
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"strings"
+
+	genericinterperter "github.com/mh-cbon/gigo/interpreter/generic"
+	glanginterpreter "github.com/mh-cbon/gigo/interpreter/glang"
+	gigolexer "github.com/mh-cbon/gigo/lexer/gigo"
+	glang "github.com/mh-cbon/gigo/struct/glang"
+	lexer "github.com/mh-cbon/state-lexer"
+)
+
+// ParseFile parses a regular go file with go/parser
+// and converts it to a gigo FileDecl, see FromFile.
+// If src is nil the file is read from the disk.
+func ParseFile(fset *token.FileSet, filename string, src interface{}) (*glang.FileDecl, error) {
+	f, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	content, err := readSource(filename, src)
+	if err != nil {
+		return nil, err
+	}
+	return FromFile(fset, f, content)
+}
+
+func readSource(filename string, src interface{}) ([]byte, error) {
+	switch s := src.(type) {
+	case nil:
+		return ioutil.ReadFile(filename)
+	case string:
+		return []byte(s), nil
+	case []byte:
+		return s, nil
+	}
+	return nil, fmt.Errorf("invalid source type %T", src)
+}
+
+// FromFile converts a go/ast file to a gigo FileDecl,
+// content is the source f was parsed from.
+//
+// The declarations are rewritten in the subset gigo interprets,
+// a grouped type ( A struct{}; B struct{} ) is split into one type declaration per type,
+// an import "fmt" is written import ("fmt").
+// Each declaration remains on its original line,
+// so the spans of the nodes point to the go source.
+func FromFile(fset *token.FileSet, f *ast.File, content []byte) (*glang.FileDecl, error) {
+	name := fset.Position(f.Package).Filename
+	src := normalize(fset, f, content)
+
+	l := lexer.New(strings.NewReader(src), (gigolexer.New()).StartHere)
+	l.ErrorHandler = func(e string) {}
+	reader := genericinterperter.NewReadFileTokenWithPos(l, name)
+
+	interpret := glanginterpreter.NewGigoInterpreter(reader)
+	return interpret.ProcessFile(name)
+}
+
+// normalize prints the declarations of f in the subset of go gigo interprets.
+func normalize(fset *token.FileSet, f *ast.File, content []byte) string {
+	w := &lineWriter{}
+	source := func(from, to token.Pos) string {
+		return string(content[fset.Position(from).Offset:fset.Position(to).Offset])
+	}
+	line := func(p token.Pos) int {
+		return fset.Position(p).Line
+	}
+	start := func(doc *ast.CommentGroup, p token.Pos) token.Pos {
+		if doc != nil {
+			return doc.Pos()
+		}
+		return p
+	}
+
+	from := start(f.Doc, f.Package)
+	w.WriteAt(line(from), source(from, f.Name.End()))
+
+	for _, d := range f.Decls {
+		switch x := d.(type) {
+		case *ast.FuncDecl:
+			from := start(x.Doc, x.Pos())
+			w.WriteAt(line(from), source(from, x.End()))
+		case *ast.GenDecl:
+			if x.Tok == token.IMPORT && !x.Lparen.IsValid() {
+				w.WriteAt(line(x.Pos()), "import ("+source(x.Specs[0].Pos(), x.End())+")")
+				continue
+			}
+			if x.Tok != token.TYPE || !x.Lparen.IsValid() {
+				from := start(x.Doc, x.Pos())
+				w.WriteAt(line(from), source(from, x.End()))
+				continue
+			}
+			for _, s := range x.Specs {
+				spec := s.(*ast.TypeSpec)
+				from := spec.Pos()
+				if spec.Doc != nil {
+					w.WriteAt(line(spec.Doc.Pos()), source(spec.Doc.Pos(), spec.Doc.End()))
+				}
+				w.WriteAt(line(from), "type "+source(from, spec.End()))
+			}
+		}
+	}
+	w.WriteAt(w.line+1, "")
+	return w.String()
+}
+
+// lineWriter writes chunks of source at given lines.
+type lineWriter struct {
+	bytes.Buffer
+	line int
+}
+
+// WriteAt writes s at the beginning of the line l,
+// or on the next line if the writer is already passed it.
+func (w *lineWriter) WriteAt(l int, s string) {
+	if w.line == 0 {
+		w.line = 1
+	} else {
+		w.WriteString("\n")
+		w.line++
+	}
+	for ; w.line < l; w.line++ {
+		w.WriteString("\n")
+	}
+	w.WriteString(s)
+	w.line += strings.Count(s, "\n")
+}
+
+// ToFile converts a gigo scope to a go/ast file.
+// The scope must be plain go with a package declaration,
+// the output of gen for example.
+func ToFile(fset *token.FileSet, scope genericinterperter.Expressioner) (*ast.File, error) {
+	name := ""
+	if f, ok := scope.(*glang.FileDecl); ok {
+		name = f.Name
+	}
+	ret, err := parser.ParseFile(fset, name, scope.String(), parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("not a go source: %v", err)
+	}
+	return ret, nil
+}
+
+// ToDecl converts a declaration such as a StructDecl or a FuncDecl to a go/ast declaration.
+func ToDecl(fset *token.FileSet, decl genericinterperter.Expressioner) (ast.Decl, error) {
+	src := "package p\n" + decl.String()
+	f, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("not a go declaration: %v", err)
+	}
+	if len(f.Decls) != 1 {
+		return nil, fmt.Errorf("wanted one declaration, got %v", len(f.Decls))
+	}
+	return f.Decls[0], nil
+}
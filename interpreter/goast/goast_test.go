@@ -0,0 +1,134 @@
+package goast
+
+import (
+	"go/ast"
+	"go/token"
+	"testing"
+
+	glang "github.com/mh-cbon/gigo/struct/glang"
+)
+
+var goSrc = `// Package tomate is a regular go file.
+package tomate
+
+import "fmt"
+
+type (
+	// Todo is a task.
+	Todo struct {
+		Name string
+	}
+	Todos struct {
+		items []Todo
+	}
+)
+
+type Pusher interface {
+	Push(t Todo)
+}
+
+// Push adds a task.
+func (s *Todos) Push(t Todo) {
+	fmt.Println(t.Name)
+	s.items = append(s.items, t)
+}
+`
+
+func TestParseFile(t *testing.T) {
+	fset := token.NewFileSet()
+	f, err := ParseFile(fset, "tomate.go", goSrc)
+	if err != nil {
+		t.Errorf("%#v\n", err)
+		return
+	}
+
+	structs := f.FindStructsTypes()
+	if len(structs) != 2 {
+		t.Errorf("unexpected structs len wanted=%v, got=%v", 2, len(structs))
+		return
+	}
+	if got := structs[0].GetName(); got != "Todo" {
+		t.Errorf("unexpected struct name wanted=%q, got=%q", "Todo", got)
+	}
+	if got := structs[0].GetFields()[0].Name; got != "Name" {
+		t.Errorf("unexpected field name wanted=%q, got=%q", "Name", got)
+	}
+	// the grouped declaration is split, but the lines remain.
+	if got := structs[1].GetSpan().Start.Line; got != 10 {
+		t.Errorf("unexpected struct line wanted=%v, got=%v", 10, got)
+	}
+
+	funcs, err := f.Select("func:Todos.Push")
+	if err != nil {
+		t.Errorf("%#v\n", err)
+		return
+	}
+	if len(funcs) != 1 {
+		t.Errorf("unexpected funcs len wanted=%v, got=%v", 1, len(funcs))
+		return
+	}
+	fn, ok := funcs[0].(*glang.FuncDecl)
+	if !ok {
+		t.Errorf("unexpected func type wanted=%v, got=%T", "*glang.FuncDecl", funcs[0])
+		return
+	}
+	if got := fn.GetSpan().String(); got != "tomate.go:19:1-24:2" {
+		t.Errorf("unexpected func span wanted=%q, got=%q", "tomate.go:19:1-24:2", got)
+	}
+	if _, err := f.Select("type:Pusher"); err != nil {
+		t.Errorf("%#v\n", err)
+	}
+}
+
+func TestParseFileErr(t *testing.T) {
+	fset := token.NewFileSet()
+	if _, err := ParseFile(fset, "tomate.go", "package tomate\ntemplate <:.Name>Slice struct{}\n"); err == nil {
+		t.Errorf("unexpected error wanted=%v, got=%v", "an error", err)
+	}
+}
+
+func TestParseFileConversion(t *testing.T) {
+	fset := token.NewFileSet()
+	src := "package tomate\nfunc (i Item) Show() { fmt.Println(string(i.B)) }\n"
+	if _, err := ParseFile(fset, "tomate.go", src); err != nil {
+		t.Errorf("%#v\n", err)
+	}
+}
+
+func TestToFile(t *testing.T) {
+	fset := token.NewFileSet()
+	f, err := ParseFile(fset, "tomate.go", goSrc)
+	if err != nil {
+		t.Errorf("%#v\n", err)
+		return
+	}
+
+	file, err := ToFile(token.NewFileSet(), f)
+	if err != nil {
+		t.Errorf("%#v\n", err)
+		return
+	}
+	if got := file.Name.Name; got != "tomate" {
+		t.Errorf("unexpected package wanted=%q, got=%q", "tomate", got)
+	}
+	if got := len(file.Decls); got != 5 {
+		t.Errorf("unexpected decls len wanted=%v, got=%v", 5, got)
+	}
+
+	decl, err := ToDecl(token.NewFileSet(), f.FindStructsTypes()[1])
+	if err != nil {
+		t.Errorf("%#v\n", err)
+		return
+	}
+	spec := decl.(*ast.GenDecl).Specs[0].(*ast.TypeSpec)
+	if got := spec.Name.Name; got != "Todos" {
+		t.Errorf("unexpected type name wanted=%q, got=%q", "Todos", got)
+	}
+	if _, ok := spec.Type.(*ast.StructType); !ok {
+		t.Errorf("unexpected type wanted=%v, got=%T", "*ast.StructType", spec.Type)
+	}
+
+	if _, err := ToDecl(token.NewFileSet(), f); err == nil {
+		t.Errorf("unexpected error wanted=%v, got=%v", "an error", err)
+	}
+}
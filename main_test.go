@@ -0,0 +1,27 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/mh-cbon/gigo/gigotest"
+	program "github.com/mh-cbon/gigo/program/glang"
+)
+
+func TestGen(t *testing.T) {
+	gigotest.RunGlob(t, "testdata/*.gigo.go")
+}
+
+func TestGenGenerics(t *testing.T) {
+	cases, err := gigotest.Glob("testdata/generics/*.gigo.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range cases {
+		c.Backend = program.Generics
+	}
+	gigotest.Run(t, cases)
+}
+
+func TestStdExamples(t *testing.T) {
+	gigotest.RunGlob(t, "templates/std/examples/*/*.gigo")
+}
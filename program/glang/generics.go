@@ -0,0 +1,203 @@
+package program
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"regexp"
+	"strings"
+
+	genericinterperter "github.com/mh-cbon/gigo/interpreter/generic"
+	glang "github.com/mh-cbon/gigo/struct/glang"
+)
+
+// Backend is the way the types of the templates are emitted.
+type Backend string
+
+const (
+	// Expand writes a copy of a template per instantiation, it is the default.
+	Expand Backend = "expand"
+	// Generics writes a template as a generic type, Slice[T any],
+	// and its instantiations as aliases, type TodoSlice = Slice[Todo].
+	// The templates that can not be written with a type parameter are expanded.
+	Generics Backend = "generics"
+)
+
+// ParseBackend returns the backend named s, expand or generics.
+func ParseBackend(s string) (Backend, error) {
+	switch b := Backend(s); b {
+	case Expand, Generics:
+		return b, nil
+	}
+	return "", fmt.Errorf("unknown backend %q, wanted %v or %v", s, Expand, Generics)
+}
+
+// typeParam is the type parameter of a generic type, it replaces .Name.
+const typeParam = "T"
+
+var (
+	tplExprRe     = regexp.MustCompile(`<:-?\s*(.*?)\s*-?:>`)
+	nameExprRe    = regexp.MustCompile(`^\$?\.Name$`)
+	typeParamRe   = regexp.MustCompile(`\b` + typeParam + `\b`)
+	baseNameRe    = regexp.MustCompile(`^[A-Za-z]\w*$`)
+	nameUseRe     = regexp.MustCompile(`\w*\x00\w*`) // a name written with .Name, \x00Slice.
+	nameInWordRe  = regexp.MustCompile(`\w\x00|\x00\w`)
+	genericTypeRe = regexp.MustCompile(`\btype\s+(\w+)\[` + typeParam + `\]`)
+)
+
+// genericType is a template written with a type parameter.
+type genericType struct {
+	Name string // the name of the template without .Name, Slice.
+	Src  string // the generic type and its methods.
+}
+
+// genericType returns the template written as a generic type, or nil if it can not be.
+func (t *TypeMutator) genericType() *genericType {
+	if !t.genericChecked {
+		t.genericChecked = true
+		t.generic = newGenericType(t)
+	}
+	return t.generic
+}
+
+// newGenericType writes the template of m with a type parameter, Slice[T any].
+// The template qualifies if .Name is its only expression and it is written as a type,
+// <:.Name>Slice, []<:.Name>, map[string]*<:.Name>.
+// It does not qualify if it declares params or declarations along its type,
+// or if the generic source does not type check, see typeCheck.
+// The type parameter is comparable if the template compares its values, item == search.
+// It returns nil if the template does not qualify.
+func newGenericType(m *TypeMutator) *genericType {
+	decl := m.Decl
+	if decl.Params != nil || len(decl.Extras) > 0 {
+		return nil
+	}
+	for _, f := range decl.Methods {
+		if f.GetModifier() != nil {
+			return nil
+		}
+	}
+	// every .Name is written \x00, the other expressions disqualify the template.
+	canon := func(s string) (string, bool) {
+		ok := true
+		s = tplExprRe.ReplaceAllStringFunc(s, func(e string) string {
+			if !nameExprRe.MatchString(tplExprRe.FindStringSubmatch(e)[1]) {
+				ok = false
+			}
+			return "\x00"
+		})
+		return s, ok
+	}
+	name, ok := canon(decl.GetName())
+	base := strings.Replace(name, "\x00", "", -1)
+	if !ok || strings.Count(name, "\x00") != 1 || !baseNameRe.MatchString(base) {
+		return nil
+	}
+	src, ok := canon(m.getTemplateStr())
+	if !ok || typeParamRe.MatchString(src) {
+		return nil
+	}
+	// the uses of the template name are the generic type, Slice[T].
+	src = nameUseRe.ReplaceAllStringFunc(src, func(e string) string {
+		if e != name {
+			return e
+		}
+		return base + "[" + typeParam + "]"
+	})
+	// the other uses of .Name must be types, New<:.Name> is not.
+	if nameInWordRe.MatchString(src) {
+		return nil
+	}
+	src = strings.Replace(src, "\x00", typeParam, -1)
+	// the type declares its type parameter.
+	var loc []int
+	for _, l := range genericTypeRe.FindAllStringSubmatchIndex(src, -1) {
+		if src[l[2]:l[3]] == base {
+			loc = l[:2]
+			break
+		}
+	}
+	if loc == nil {
+		return nil
+	}
+	// T any, unless the template compares the values of T, T comparable.
+	for _, constraint := range []string{"any", "comparable"} {
+		res := src[:loc[1]-1] + " " + constraint + "]" + src[loc[1]:]
+		if err := typeCheck(base, res, m.imports); err != nil {
+			continue
+		}
+		fileDef, err := InterpretString(base, "package generic\n\n"+res)
+		if err != nil {
+			return nil
+		}
+		if structs := fileDef.FindStructsTypes(); len(structs) == 0 || structs[0].TypeParams == nil {
+			return nil
+		}
+		return &genericType{Name: base, Src: res}
+	}
+	return nil
+}
+
+var (
+	checkFset     = token.NewFileSet()
+	checkImporter = importer.ForCompiler(checkFset, "source", nil)
+)
+
+// typeCheck returns an error if the generic source src does not compile on its own,
+// with the imports of the gigo file.
+// It uses a field or a method of T, it embeds T, or it uses a declaration of the package.
+func typeCheck(name, src string, imports []glang.ImportSpec) error {
+	head := "package generic\n\n"
+	for _, i := range imports {
+		head += fmt.Sprintf("import %v %q\n", i.Name, i.Path)
+	}
+	f, err := parser.ParseFile(checkFset, name, head+"\n"+src, 0)
+	if err != nil {
+		return err
+	}
+	var firstErr error
+	conf := types.Config{
+		Importer: checkImporter,
+		Error: func(err error) {
+			// the imports the template does not use are soft errors.
+			if e, ok := err.(types.Error); ok && e.Soft {
+				return
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+		},
+	}
+	conf.Check("generic", checkFset, []*ast.File{f}, nil)
+	return firstErr
+}
+
+// genericDecls returns the declarations of the type name, the instantiation of g with arg,
+// type TodoSlice = Slice[Todo].
+// The generic type is declared along its first instantiation in the package.
+func (c *Instances) genericDecls(g *genericType, name, arg string) ([]genericinterperter.Tokener, error) {
+	src := ""
+	if other, ok := c.generics[g.Name]; !ok {
+		c.generics[g.Name] = g.Src
+		src += g.Src + "\n\n"
+	} else if other != g.Src {
+		return nil, fmt.Errorf("the generic type %v of %v is declared by another template", g.Name, name)
+	}
+	src += fmt.Sprintf("type %v = %v[%v]\n", name, g.Name, arg)
+	fileDef, err := InterpretString(name, "package generic\n\n"+src)
+	if err != nil {
+		return nil, err
+	}
+	var ret []genericinterperter.Tokener
+	for _, x := range fileDef.Tokens {
+		switch x.(type) {
+		case *glang.PackageDecl, *genericinterperter.TokenWithPos: // ws and comments.
+			continue
+		}
+		ret = append(ret, x)
+	}
+	return ret, nil
+}
@@ -0,0 +1,101 @@
+package program
+
+import (
+	"fmt"
+	"path"
+	"unicode"
+	"unicode/utf8"
+
+	glang "github.com/mh-cbon/gigo/struct/glang"
+)
+
+// interfaceFuncName is the built-in mutation that derives an interface from a method set,
+// type TodosAPI implements<:Interface .Todos true> {}
+// emits type TodosAPI interface {...} with the exported methods of Todos.
+const interfaceFuncName = "Interface"
+
+// getInterfaceFunc returns the built-in Interface mutation,
+// it keeps the methods of target that are exported if exported is true,
+// and whose name matches one of the patterns, if any, Get*, Push.
+func (t *ImplTypeMutation) getInterfaceFunc() func(target interface{}, exported bool, patterns ...string) (*glang.InterfaceDecl, error) {
+	return func(target interface{}, exported bool, patterns ...string) (*glang.InterfaceDecl, error) {
+		name, methods, err := t.methodsOf(target)
+		if err != nil {
+			t.err = fmt.Errorf("%v, at %v", err, t.Decl.ImplementTemplate.GetSpan())
+			return nil, t.err
+		}
+		methods, err = filterMethods(methods, exported, patterns)
+		if err != nil {
+			t.err = fmt.Errorf("%v, at %v", err, t.Decl.ImplementTemplate.GetSpan())
+			return nil, t.err
+		}
+		res, err := deriveInterface(name+"Interface", methods)
+		if err != nil {
+			t.err = err
+			return nil, err
+		}
+		t.last = nil
+		t.lastInterface = res
+		return res, nil
+	}
+}
+
+// methodsOf returns the name and the pointer method set of a struct, an interface, or an implements declaration,
+// the methods promoted by the embedded types are included.
+func (t *ImplTypeMutation) methodsOf(target interface{}) (string, []glang.FuncDeclarer, error) {
+	if x, ok := target.(*glang.ImplementDecl); ok {
+		res, err := t.types.resolve(x)
+		if err != nil {
+			return "", nil, err
+		}
+		target = res
+	}
+	switch x := target.(type) {
+	case *glang.StructDecl:
+		methods, err := t.types.methodSet(x, true)
+		return x.GetName(), methods, err
+	case *glang.InterfaceDecl:
+		methods, err := t.types.methodSet(x, true)
+		return x.GetName(), methods, err
+	}
+	return "", nil, fmt.Errorf("the target %#v of the template %v has no methods", target, interfaceFuncName)
+}
+
+// filterMethods returns the methods that are exported if exported is true,
+// and whose name matches one of the patterns, if any.
+func filterMethods(methods []glang.FuncDeclarer, exported bool, patterns []string) ([]glang.FuncDeclarer, error) {
+	ret := []glang.FuncDeclarer{}
+	for _, m := range methods {
+		name := m.GetName()
+		if r, _ := utf8.DecodeRuneInString(name); exported && !unicode.IsUpper(r) {
+			continue
+		}
+		ok := len(patterns) == 0
+		for _, p := range patterns {
+			matched, err := path.Match(p, name)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern %q of the template %v: %v", p, interfaceFuncName, err)
+			}
+			ok = ok || matched
+		}
+		if ok {
+			ret = append(ret, m)
+		}
+	}
+	return ret, nil
+}
+
+// deriveInterface returns an interface named name of the signatures of methods.
+func deriveInterface(name string, methods []glang.FuncDeclarer) (*glang.InterfaceDecl, error) {
+	src := "package derived\n\ntype " + name + " interface {\n"
+	for _, m := range methods {
+		v := glang.NewMethodView(m)
+		src += "\t" + v.Name + "(" + v.ParamsDecl() + ") " + v.ResultsDecl() + "\n"
+	}
+	src += "}\n"
+	fileDef, err := InterpretString("derived", src)
+	if err != nil {
+		return nil, err
+	}
+	return fileDef.FindInterfaces()[0], nil
+}
@@ -0,0 +1,92 @@
+package program
+
+import (
+	"fmt"
+	"strings"
+
+	glang "github.com/mh-cbon/gigo/struct/glang"
+)
+
+// Instances caches the types generated by the templates of a package,
+// an instantiation is keyed by its template, its target type and its args,
+// so every type is generated, and emitted, only once.
+type Instances struct {
+	Backend Backend // the way the types are emitted, Expand or Generics.
+	// Loader loads the imported packages and the libraries of the package,
+	// it is created for the directory of the first file mutated if it is nil.
+	Loader *Loader
+
+	types    map[string]*glang.StructDecl
+	names    map[string]string // the key of the instantiation of a generated type name.
+	descs    map[string]string // the instantiation of a key, as written, Slice Todo "Name".
+	generics map[string]string // the source of a generic type already emitted, by name.
+}
+
+// NewInstances creates a new Instances cache.
+func NewInstances() *Instances {
+	return &Instances{
+		Backend:  Expand,
+		types:    map[string]*glang.StructDecl{},
+		names:    map[string]string{},
+		descs:    map[string]string{},
+		generics: map[string]string{},
+	}
+}
+
+func instanceKey(m *TypeMutator, origin *glang.StructDecl, args []interface{}) (key, desc string) {
+	// the template source identifies the template, its name depends on the use.
+	a := []string{}
+	for _, x := range args {
+		a = append(a, argKey(x))
+	}
+	key = m.Decl.String() + "\x00" + origin.GetQualifiedName() + "\x00" + strings.Join(a, " ")
+	desc = strings.TrimSpace(m.Name + " " + origin.GetQualifiedName() + " " + strings.Join(a, " "))
+	return key, desc
+}
+
+// argKey returns an arg of an instantiation as written in its key,
+// a type is keyed by its name, so equal instantiations share their key.
+func argKey(x interface{}) string {
+	switch a := x.(type) {
+	case *glang.StructDecl:
+		return a.GetQualifiedName()
+	case *glang.InterfaceDecl:
+		return a.GetQualifiedName()
+	case *TypeArg:
+		return a.GetQualifiedName()
+	case []interface{}:
+		ret := []string{}
+		for _, y := range a {
+			ret = append(ret, argKey(y))
+		}
+		return "[" + strings.Join(ret, " ") + "]"
+	case fmt.Stringer:
+		return a.String()
+	}
+	return fmt.Sprintf("%#v", x)
+}
+
+// get returns the type of an instantiation, or nil.
+func (c *Instances) get(key string) *glang.StructDecl {
+	return c.types[key]
+}
+
+// byName returns the generated type named name, or nil.
+func (c *Instances) byName(name string) *glang.StructDecl {
+	if k, ok := c.names[name]; ok {
+		return c.types[k]
+	}
+	return nil
+}
+
+// add records the type of an instantiation described by desc,
+// it returns an error if another instantiation generated a type with the same name.
+func (c *Instances) add(key, desc string, s *glang.StructDecl) error {
+	if k, ok := c.names[s.GetName()]; ok && k != key {
+		return fmt.Errorf("the type %v is generated by %v and by %v", s.GetName(), c.descs[k], desc)
+	}
+	c.types[key] = s
+	c.names[s.GetName()] = key
+	c.descs[key] = desc
+	return nil
+}
@@ -0,0 +1,73 @@
+package program
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	genericinterperter "github.com/mh-cbon/gigo/interpreter/generic"
+	glanginterpreter "github.com/mh-cbon/gigo/interpreter/glang"
+	gigolexer "github.com/mh-cbon/gigo/lexer/gigo"
+	glang "github.com/mh-cbon/gigo/struct/glang"
+	lexer "github.com/mh-cbon/state-lexer"
+)
+
+func makeLexerReader(r io.Reader, fileName string) genericinterperter.TokenerReader {
+
+	l := lexer.New(r, (gigolexer.New()).StartHere)
+	l.ErrorHandler = func(e string) {}
+
+	return genericinterperter.NewReadFileTokenWithPos(l, fileName)
+}
+
+func prettyPrinterLexer(reader genericinterperter.TokenerReader) genericinterperter.TokenerReader {
+
+	namer := genericinterperter.TokenerName(gigolexer.TokenName)
+	reader = genericinterperter.NewReadNPrettyPrint(reader, namer, os.Stdout)
+
+	return reader
+}
+
+func InterpretFile(fileName string) (*glang.FileDecl, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	reader := makeLexerReader(f, fileName)
+	// reader = prettyPrinterLexer(reader)
+
+	interpret := glanginterpreter.NewGigoInterpreter(reader)
+	return interpret.ProcessFile(fileName)
+}
+
+func InterpretString(pkgName, content string) (*glang.StrDecl, error) {
+
+	var buf bytes.Buffer
+	buf.WriteString(content)
+	reader := makeLexerReader(&buf, "")
+	//reader = prettyPrinterLexer(reader)
+
+	interpret := glanginterpreter.NewGigoInterpreter(reader)
+	return interpret.ProcessStr(content)
+}
+
+func MustInterpretFile(fileName string) *glang.FileDecl {
+	ret, err := InterpretFile(fileName)
+	if err != nil {
+		fmt.Printf("%#v\n", err)
+		fmt.Printf("%+v\n", err)
+		panic(err)
+	}
+	return ret
+}
+
+func MustInterpretString(name, content string) *glang.StrDecl {
+	ret, err := InterpretString(name, content)
+	if err != nil {
+		fmt.Printf("%#v\n", err)
+		panic(err)
+	}
+	return ret
+}
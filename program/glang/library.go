@@ -0,0 +1,90 @@
+package program
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	glang "github.com/mh-cbon/gigo/struct/glang"
+)
+
+// Library is a set of templates declared in the gigo files of a directory,
+// a gigo file uses it with a //gigo:use directive.
+type Library struct {
+	Path      string
+	Templates []*glang.TemplateDecl     // the templates with their methods attached.
+	Defines   []*glang.TemplateFuncDecl // the <define> funcs.
+}
+
+// LoadLibrary returns the library at importPath,
+// its gigo files, *.gigo.go and *.gigo, are interpreted only once.
+// The library must not be modified.
+func (l *Loader) LoadLibrary(importPath string) (*Library, error) {
+	if lib, ok := l.libraries[importPath]; ok {
+		return lib, nil
+	}
+	dir, err := l.Dir(importPath)
+	if err != nil {
+		return nil, err
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, f := range files {
+		if !f.IsDir() && (strings.HasSuffix(f.Name(), ".gigo.go") || strings.HasSuffix(f.Name(), ".gigo")) {
+			names = append(names, filepath.Join(dir, f.Name()))
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no gigo files found in %v", dir)
+	}
+	sort.Strings(names)
+
+	ret := &Library{Path: importPath}
+	for _, name := range names {
+		fileDef, err := InterpretFile(name)
+		if err != nil {
+			return nil, err
+		}
+		ret.Templates = append(ret.Templates, fileDef.FindTemplatesTypes()...)
+		ret.Defines = append(ret.Defines, fileDef.FindDefineFuncs()...)
+		for _, m := range fileDef.FindTemplateFuncs() {
+			if err := ret.attachMethod(m); err != nil {
+				return nil, fmt.Errorf("%v: %v", name, err)
+			}
+		}
+		for _, e := range fileDef.FindTemplateExtras() {
+			if err := attachExtra(ret.Templates, e); err != nil {
+				return nil, fmt.Errorf("%v: %v", name, err)
+			}
+		}
+	}
+	l.libraries[importPath] = ret
+	return ret, nil
+}
+
+func (lib *Library) attachMethod(m glang.FuncDeclarer) error {
+	if x, ok := m.GetReceiverType().First().(*glang.IdentifierDecl); ok {
+		for _, t := range lib.Templates {
+			if t.GetSlugName() == x.GetSlugName() {
+				t.AddMethod(m)
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("template not found for the method %v", m.GetName())
+}
+
+// GetTemplate returns the template with given slug name, or nil.
+func (lib *Library) GetTemplate(name string) *glang.TemplateDecl {
+	for _, t := range lib.Templates {
+		if t.GetSlugName() == name {
+			return t
+		}
+	}
+	return nil
+}
@@ -0,0 +1,222 @@
+package program
+
+import (
+	"fmt"
+
+	glang "github.com/mh-cbon/gigo/struct/glang"
+)
+
+// typeRegistry finds the types declared by a gigo file,
+// its structs, its interfaces, its implements and the types generated by the templates.
+type typeRegistry struct {
+	data      map[string]interface{} // the structs, the interfaces and the implements by name.
+	instances *Instances
+	impls     map[*glang.ImplementDecl]*placeholderTypeMutation
+	mutators  []*TypeMutator
+	pkgFuncs  map[string]interface{} // the funcs of the imported packages, see importFuncs.
+}
+
+// lookup returns the struct or the interface named name, or nil.
+// An implements is executed, if it was not yet, to return its type.
+func (r *typeRegistry) lookup(name string) interface{} {
+	if r == nil {
+		return nil
+	}
+	switch x := r.data[name].(type) {
+	case *glang.StructDecl, *glang.InterfaceDecl:
+		return x
+	case *glang.ImplementDecl:
+		if res, err := r.resolve(x); err == nil {
+			return res
+		}
+		return nil
+	}
+	if s := r.instances.byName(name); s != nil {
+		return s
+	}
+	return nil
+}
+
+// declares returns true if the package declares a type named name,
+// or if a template generated it.
+func (r *typeRegistry) declares(name string) bool {
+	if r == nil {
+		return false
+	}
+	_, ok := r.data[name]
+	return ok || r.instances.byName(name) != nil
+}
+
+// resolve returns the type declared by the implements i,
+// a struct with the methods declared on it, or an interface.
+func (r *typeRegistry) resolve(i *glang.ImplementDecl) (interface{}, error) {
+	p := r.impls[i]
+	if p == nil {
+		return nil, fmt.Errorf("the implements %v is not declared by this file", i.GetName())
+	}
+	if _, err := p.execute(r.mutators, r.data); err != nil {
+		return nil, err
+	}
+	return p.mutation.resolved()
+}
+
+// imported returns the struct or the interface name of the imported package pkg.
+func (r *typeRegistry) imported(pkg, name string) (interface{}, error) {
+	f, ok := r.pkgFuncs[pkg].(func() (map[string]interface{}, error))
+	if !ok {
+		return nil, fmt.Errorf("the package %v of the type %v.%v is not imported", pkg, pkg, name)
+	}
+	types, err := f()
+	if err != nil {
+		return nil, err
+	}
+	if x := types[name]; x != nil {
+		return x, nil
+	}
+	return nil, fmt.Errorf("the type %v.%v is not a struct or an interface of its package", pkg, name)
+}
+
+// embedded is a type reached by the embedded fields of another type.
+type embedded struct {
+	decl    interface{}
+	pointer bool // the methods of *T are promoted.
+}
+
+// levels returns the types reached from decl by its embedded fields, by depth,
+// decl is the only type of the depth 0, with the method set of *T if pointer is true.
+// A type embedded twice at the same depth, A and B embed C, is listed twice,
+// a type of a lower depth is not listed again.
+// Embedded types of other packages, store.Item, are loaded from their sources,
+// it returns an error if their package can not be loaded, sync.Mutex.
+func (r *typeRegistry) levels(decl interface{}, pointer bool) ([][]embedded, error) {
+	ret := [][]embedded{}
+	visited := map[interface{}]bool{decl: true} // the types of a lower depth.
+	level := []embedded{{decl, pointer}}
+	for len(level) > 0 {
+		ret = append(ret, level)
+		next := []embedded{}
+		for _, e := range level {
+			_, fields := membersOf(e.decl)
+			for _, f := range fields {
+				if !f.Embedded {
+					continue
+				}
+				base := f.Type.Base()
+				var x interface{}
+				if base.Pkg() != "" {
+					var err error
+					if x, err = r.imported(base.Pkg(), base.Name()); err != nil {
+						return nil, fmt.Errorf("the embedded field %v: %v", f.Type, err)
+					}
+				} else {
+					x = r.lookup(base.Name())
+				}
+				if x != nil && !visited[x] {
+					next = append(next, embedded{x, e.pointer || f.Type.IsPointer()})
+				}
+			}
+		}
+		for _, e := range next {
+			visited[e.decl] = true
+		}
+		level = next
+	}
+	return ret, nil
+}
+
+// methodSet returns the methods of the type decl, declared or promoted by its embedded fields,
+// the method set of *T if pointer is true, the method set of T otherwise.
+// A selector declared twice at the same depth is ambiguous, it is not part of the method set,
+// see levels.
+func (r *typeRegistry) methodSet(decl interface{}, pointer bool) ([]glang.FuncDeclarer, error) {
+	levels, err := r.levels(decl, pointer)
+	if err != nil {
+		return nil, err
+	}
+	ret := []glang.FuncDeclarer{}
+	seen := map[string]bool{} // the selectors of a lower depth.
+	for _, level := range levels {
+		count := map[string]int{}
+		found := map[string]glang.FuncDeclarer{}
+		order := []string{}
+		for _, e := range level {
+			methods, fields := membersOf(e.decl)
+			for _, m := range methods {
+				name := m.GetName()
+				count[name]++
+				// a method of *T is not in the method set of T.
+				if e.pointer || !m.IsMethod() || !glang.NewMethodView(m).IsPointerReceiver() {
+					found[name] = m
+					order = append(order, name)
+				}
+			}
+			for _, f := range fields {
+				count[f.Name]++
+			}
+		}
+		for _, name := range order {
+			if !seen[name] && count[name] == 1 {
+				ret = append(ret, found[name])
+			}
+		}
+		for name := range count {
+			seen[name] = true
+		}
+	}
+	return ret, nil
+}
+
+// selector is a field or a method of a type, declared or promoted.
+type selector struct {
+	Name  string
+	Kind  string // field or method.
+	Depth int    // 0 if it is declared by the type.
+}
+
+// selectors returns the fields and the methods of *T of the type decl,
+// declared or promoted by its embedded fields, at the lowest depth they are found.
+func (r *typeRegistry) selectors(decl interface{}) ([]selector, error) {
+	levels, err := r.levels(decl, true)
+	if err != nil {
+		return nil, err
+	}
+	ret := []selector{}
+	seen := map[string]bool{}
+	for depth, level := range levels {
+		for _, e := range level {
+			methods, fields := membersOf(e.decl)
+			for _, f := range fields {
+				if !seen[f.Name] {
+					seen[f.Name] = true
+					ret = append(ret, selector{f.Name, "field", depth})
+				}
+			}
+			for _, m := range methods {
+				if !seen[m.GetName()] {
+					seen[m.GetName()] = true
+					ret = append(ret, selector{m.GetName(), "method", depth})
+				}
+			}
+		}
+	}
+	return ret, nil
+}
+
+// membersOf returns the methods and the fields of a struct or of an interface,
+// the embedded interfaces are the embedded fields of an interface.
+func membersOf(decl interface{}) ([]glang.FuncDeclarer, []*glang.Field) {
+	switch x := decl.(type) {
+	case *glang.StructDecl:
+		return x.Methods, x.GetFields()
+	case *glang.InterfaceDecl:
+		fields := []*glang.Field{}
+		if x.Block != nil {
+			for _, u := range x.Block.Underlying {
+				t := glang.NewTypeRef(u.String())
+				fields = append(fields, &glang.Field{Name: t.Base().Name(), Type: t, Embedded: true})
+			}
+		}
+		return x.GetMethods(), fields
+	}
+	return nil, nil
+}
@@ -0,0 +1,1173 @@
+package program
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	genericinterperter "github.com/mh-cbon/gigo/interpreter/generic"
+	genericlexer "github.com/mh-cbon/gigo/lexer/generic"
+	glanglexer "github.com/mh-cbon/gigo/lexer/glang"
+	glang "github.com/mh-cbon/gigo/struct/glang"
+	lexer "github.com/mh-cbon/state-lexer"
+)
+
+// Mutate executes the templates of a gigo file,
+// the result is a regular go source.
+func Mutate(fileDef *glang.FileDecl) (glang.ScopeReceiver, error) {
+	return MutateWith(fileDef, NewInstances())
+}
+
+// MutatePackage executes the templates of the gigo files of a package,
+// the types, the templates and the <define> funcs of a file are shared with the others,
+// a type generated by a file is not generated again by the others.
+func MutatePackage(files []*glang.FileDecl) ([]glang.ScopeReceiver, error) {
+	return MutatePackageWith(files, NewInstances())
+}
+
+// MutatePackageWith executes the templates of the gigo files of a package,
+// the types already generated in instances are reused.
+func MutatePackageWith(files []*glang.FileDecl, instances *Instances) ([]glang.ScopeReceiver, error) {
+	return mutateFiles(files, instances)
+}
+
+// MutateWith executes the templates of a gigo file,
+// the types already generated in instances are reused.
+func MutateWith(fileDef *glang.FileDecl, instances *Instances) (glang.ScopeReceiver, error) {
+	ret, err := mutateFiles([]*glang.FileDecl{fileDef}, instances)
+	if err != nil {
+		return nil, err
+	}
+	return ret[0], nil
+}
+
+// mutateFiles executes the templates of the gigo files of a package,
+// their declarations are registered in one registry before any template is executed.
+func mutateFiles(files []*glang.FileDecl, instances *Instances) ([]glang.ScopeReceiver, error) {
+	if len(files) == 0 {
+		return nil, nil
+	}
+
+	// the trees are modified in place below,
+	// work on copies so the parsed files remain usable.
+	fileDefs := []*glang.FileDecl{}
+	for _, f := range files {
+		fileDefs = append(fileDefs, f.Clone())
+	}
+
+	allTplsFuncs := map[string]interface{}{
+		// quote writes a go string, a template can not be written within a string.
+		"quote": func(s interface{}) string {
+			return strconv.Quote(fmt.Sprint(s))
+		},
+		"joinexpr": func(glue string, tokens interface{}) string {
+			t := []genericinterperter.Tokener{}
+			switch yy := tokens.(type) {
+			case []genericinterperter.Tokener:
+				t = append(t, yy...)
+			case []*glang.PropDecl:
+				for _, xx := range yy {
+					t = append(t, xx)
+				}
+			case []*glang.IdentifierDecl:
+				for _, xx := range yy {
+					t = append(t, xx)
+				}
+			}
+			ret := []string{}
+			for _, xx := range t {
+				ret = append(ret, xx.String())
+			}
+			return strings.Join(ret, glue)
+		},
+	}
+
+	tplTypesFuncs := map[string]interface{}{}
+	// where the <define> funcs are declared by name, a position or a library.
+	definedIn := map[string]string{}
+	// the structs, the interfaces and the implements of the package by name.
+	implTplData := map[string]interface{}{}
+	declaredIn := map[string]string{}
+	declare := func(name string, decl interface{}, fileDef *glang.FileDecl) error {
+		if other, ok := declaredIn[name]; ok && other != fileDef.GetName() {
+			return fmt.Errorf("the type %v is declared by %v and by %v", name, other, fileDef.GetName())
+		}
+		declaredIn[name] = fileDef.GetName()
+		implTplData[name] = decl
+		return nil
+	}
+
+	// the packages and the libraries are loaded once per package.
+	if instances.Loader == nil {
+		instances.Loader = NewLoader(filepath.Dir(fileDefs[0].GetName()))
+	}
+	loader := instances.Loader
+	registry := &typeRegistry{
+		data:      implTplData,
+		instances: instances,
+		impls:     map[*glang.ImplementDecl]*placeholderTypeMutation{},
+		pkgFuncs:  map[string]interface{}{},
+	}
+
+	/* At that moment the files are processed,
+	all the template/type/struct/interface/func/ect declarations
+	are well known.
+	*/
+	// the templates of the package, a method or a template(XXX) declaration
+	// can be written in another file than its template.
+	tplTypes := []*glang.TemplateDecl{}
+	for _, fileDef := range fileDefs {
+		tplTypes = append(tplTypes, fileDef.FindTemplatesTypes()...)
+	}
+	var attachMethod = func(m glang.FuncDeclarer) error {
+		for _, t := range tplTypes {
+			if x, ok := m.GetReceiverType().First().(*glang.IdentifierDecl); ok {
+				if t.GetSlugName() == x.GetSlugName() {
+					t.AddMethod(m)
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("template not found for the method %v at %v", m.GetName(), m.GetReceiverType().GetSpan())
+	}
+
+	outs := []*Tomate{}
+	implTypes := []*glang.ImplementDecl{}
+	var mutators []*TypeMutator
+	funcsForTypesMutators := map[string]interface{}{}
+	// the uses are read before the declarations that keep them are removed.
+	uses := map[*glang.FileDecl][]glang.ImportSpec{}
+	for _, fileDef := range fileDefs {
+		uses[fileDef] = fileDef.FindUses()
+	}
+	for _, fileDef := range fileDefs {
+		outData := &Tomate{
+			implTplData: implTplData,
+		}
+		outs = append(outs, outData)
+
+		// prepare the source for its rendering
+		structTypes := fileDef.FindStructsTypes()
+		fileImplTypes := fileDef.FindImplementsTypes()
+		fileTplTypes := fileDef.FindTemplatesTypes()
+		funcs := fileDef.FindFuncs()
+		tplFuncs := fileDef.FindTemplateFuncs()
+		defFuncs := fileDef.FindDefineFuncs()
+		implTypes = append(implTypes, fileImplTypes...)
+
+		var attachImplMethod = func(m glang.FuncDeclarer) bool {
+			if m.IsMethod() {
+				for _, t := range fileImplTypes {
+					if x, ok := m.GetReceiverType().First().(*glang.IdentifierDecl); ok {
+						if t.Name.GetSlugName() == x.GetSlugName() {
+							t.AddMethod(m)
+							return true
+						}
+					}
+				}
+			}
+			return false
+		}
+		// methods of regular structs are attached to them,
+		// so templates can range over their .Methods
+		var attachStructMethod = func(m glang.FuncDeclarer) bool {
+			if m.IsMethod() {
+				name := glang.NewTypeRef(m.GetReceiverType().String()).Base().Name()
+				for _, t := range structTypes {
+					if t.GetName() == name {
+						t.AddMethod(m)
+						return true
+					}
+				}
+			}
+			return false
+		}
+
+		// type XXX implements{}, needs to be replaced by a placeholder,
+		// its template tokens values are changed to avoid further problems
+		pkgFuncs := importFuncs(fileDef, loader)
+		for k, v := range pkgFuncs {
+			if _, ok := registry.pkgFuncs[k]; !ok {
+				registry.pkgFuncs[k] = v
+			}
+		}
+		for _, i := range fileImplTypes {
+			name := fmt.Sprintf("placeholder%v", len(outData.placeholders))
+			m := NewPlaceholderTypeMutation(name, i, pkgFuncs, instances)
+			m.mutation.types = registry
+			registry.impls[i] = m
+			outData.placeholders = append(outData.placeholders, m)
+			fileDef.MustInsertAfter(i, m.PlaceholderDecl)
+			fileDef.MustRemove(i)
+			i.SetTokenValue(glanglexer.TplOpenToken, "<:")
+			i.SetTokenValue(glanglexer.TplCloseToken, ":>")
+		}
+		// template XXX<Modifier> struct {}
+		// are to be removed, they really just template expressions.
+		for _, i := range fileTplTypes {
+			fileDef.MustRemove(i)
+			i.SetTokenValue(glanglexer.TplOpenToken, "<:")
+			i.SetTokenValue(glanglexer.TplCloseToken, ":>")
+		}
+		// <Modifier> func ()
+		// and
+		// func(receiver<...>)...
+		// are to be removed, they really just template expressions.
+		// it also attaches the method to their type.
+		for _, i := range tplFuncs {
+			fileDef.MustRemove(i)
+			i.SetTokenValue(glanglexer.TplOpenToken, "<:")
+			i.SetTokenValue(glanglexer.TplCloseToken, ":>")
+			// i.GetBody().SetTokenValue(glanglexer.GreaterToken, ":>") // trick unti fix.
+			if err := attachMethod(i); err != nil {
+				return nil, err
+			}
+		}
+		// template(XXX) func/const/var/type ...
+		// are removed, they are emitted along the type of their template.
+		for _, i := range fileDef.FindTemplateExtras() {
+			fileDef.MustRemove(i)
+			i.SetTokenValue(glanglexer.TplOpenToken, "<:")
+			i.SetTokenValue(glanglexer.TplCloseToken, ":>")
+			if err := attachExtra(tplTypes, i); err != nil {
+				return nil, err
+			}
+		}
+		// <define> func XXX ()
+		// are to be removed because those funcs are injected into the template instances
+		for _, i := range defFuncs {
+			fileDef.MustRemove(i)
+			i.SetTokenValue(glanglexer.TplOpenToken, "<:")
+			i.SetTokenValue(glanglexer.TplCloseToken, ":>")
+
+			//- define a template func
+			// that func (tbd later) will be available in type declarations expressions like
+			// - implement<>
+			// - template<>
+			name := i.GetName()
+			tplTypesFuncs[name] = stubFunc(i.String())
+			definedIn[name] = fmt.Sprintf("at %v", i.GetSpan())
+			// the key difficulty in this feature is that the func string can not be
+			// evaluated at runtime, so this whole template transforms step,
+			// needs to be delayed to a new sub go program where the func body string can be written.
+			// just refactroring of the current mess!
+		}
+		// regular go fund method are attached to ehir type.
+		for _, i := range funcs {
+			if !attachImplMethod(i) {
+				attachStructMethod(i)
+			}
+		}
+
+		for _, i := range structTypes {
+			// declare regular structs as data protperties
+			if err := declare(i.GetName(), i, fileDef); err != nil {
+				return nil, err
+			}
+		}
+		// interfaces are targets too, implements<:Mock .Store>
+		for _, i := range fileDef.FindInterfaces() {
+			if err := declare(i.GetName(), i, fileDef); err != nil {
+				return nil, err
+			}
+		}
+		// the methods of an implements can be derived, implements<:Interface .Todos true>
+		for _, i := range fileImplTypes {
+			if err := declare(i.GetName(), i, fileDef); err != nil {
+				return nil, err
+			}
+		}
+
+		// for every declarations
+		// - template XXXX struct{}
+		// - func(of the template)...
+		// do
+		//- create a template.Template of its string
+		//- create a template.Func of its mutation
+		for _, i := range fileTplTypes {
+			for _, m := range mutators {
+				if m.Name == i.GetSlugName() {
+					return nil, fmt.Errorf("the template %v is declared twice, at %v and at %v", m.Name, m.Decl.GetSpan(), i.GetSpan())
+				}
+			}
+			mutators = append(mutators, &TypeMutator{
+				Decl:    i,
+				Name:    i.GetSlugName(),
+				funcs:   funcsForTypesMutators,
+				imports: fileDef.FindImports(),
+			})
+		}
+	}
+	// templates of the libraries used with //gigo:use,
+	// a library used by several files is used once.
+	localMutators := len(mutators)
+	for _, fileDef := range fileDefs {
+		libMutators, err := useLibraries(uses[fileDef], loader, tplTypes, tplTypesFuncs, definedIn)
+		if err != nil {
+			return nil, err
+		}
+	libs:
+		for _, m := range libMutators {
+			for _, other := range mutators[localMutators:] {
+				if other.Name == m.Name {
+					continue libs
+				}
+			}
+			m.funcs = funcsForTypesMutators
+			m.imports = fileDef.FindImports()
+			mutators = append(mutators, m)
+		}
+	}
+	for k, v := range tplTypesFuncs {
+		funcsForTypesMutators[k] = v
+	}
+	for k, v := range allTplsFuncs {
+		funcsForTypesMutators[k] = v
+	}
+	registry.mutators = mutators
+	for _, outData := range outs {
+		outData.tplTypesMutators = mutators
+	}
+
+	// the params and the pipelines are checked before any template is executed.
+	for _, m := range mutators {
+		if err := checkParams(m); err != nil {
+			return nil, err
+		}
+	}
+	lookup := func(name string) (*glang.TemplateDecl, bool) {
+		if name == interfaceFuncName {
+			return nil, true
+		}
+		for _, m := range mutators {
+			if m.Name == name {
+				return m.Decl, true
+			}
+		}
+		return nil, false
+	}
+	for _, i := range implTypes {
+		for _, c := range i.Chains {
+			if err := c.Validate(lookup); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	ret := []glang.ScopeReceiver{}
+	for i, fileDef := range fileDefs {
+		outData := outs[i]
+		// need to remove comments, they are not understood by template.Template,
+		// and if they contain the template syntax, it breaks becasue template evaluate them.
+		// on the other hand, GigoInterpreter does not interpret comments, so it can t see and manage those
+		// problematic strings. :/
+		// finally the idea is to lacehold the comments, its kind of noop, works well.
+		x := placeholdComments(genericlexer.CommentBlockToken, fileDef, "blockcomments")
+		outData.placeholders = append(outData.placeholders, x...)
+		y := placeholdComments(genericlexer.CommentLineToken, fileDef, "linecomments")
+		outData.placeholders = append(outData.placeholders, y...)
+
+		tplContent := fileDef.String()
+
+		// execute the modified file tree with a taylor made template context.
+		tpl := makeTplOfSource("gigo", tplContent, allTplsFuncs)
+
+		var out bytes.Buffer
+		if err := tpl.Execute(&out, outData); err != nil {
+			if outData.err != nil {
+				return nil, outData.err
+			}
+			return nil, genericinterperter.NewStringTplSyntaxError(err, "gigo", tplContent)
+		}
+		res, err := InterpretString(fileDef.GetName(), out.String())
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, res)
+	}
+	return ret, nil
+}
+
+// importFuncs returns a template func per package imported by fileDef,
+// in a decl like implements<:Slice pkg.Type>, pkg loads the package from its sources,
+// it returns its structs and its interfaces by name, they are read once.
+func importFuncs(fileDef *glang.FileDecl, loader *Loader) map[string]interface{} {
+	ret := map[string]interface{}{}
+	for _, i := range fileDef.FindImports() {
+		if i.Name == "_" || i.Name == "." {
+			continue
+		}
+		i := i
+		name := i.Name
+		if name == "" {
+			name = path.Base(i.Path) // assume the package is named after its directory.
+		}
+		var types map[string]interface{}
+		ret[name] = func() (map[string]interface{}, error) {
+			if types != nil {
+				return types, nil
+			}
+			pkg, err := loader.Load(i.Path)
+			if err != nil {
+				return nil, err
+			}
+			// the types of the package are written pkg.Type in the file.
+			declared := declaredTypes(pkg)
+			types = map[string]interface{}{}
+			for _, s := range pkg.FindStructsTypes() {
+				s = s.Clone()
+				s.Pkg = name
+				qualifyTypes(s, name, declared)
+				types[s.GetName()] = s
+			}
+			for _, s := range pkg.FindInterfaces() {
+				s = s.Clone()
+				s.Pkg = name
+				qualifyTypes(s, name, declared)
+				types[s.GetName()] = s
+			}
+			return types, nil
+		}
+	}
+	return ret
+}
+
+// useLibraries returns the mutators of the templates of the libraries
+// of the //gigo:use directives of a file.
+// A template Name of the library tpl is named tpl.Name,
+// it is also named Name if no local template and no other library declares Name.
+// The <define> funcs of the libraries are added to funcs,
+// a name declared locally or by another library is an error.
+func useLibraries(
+	uses []glang.ImportSpec,
+	loader *Loader,
+	locals []*glang.TemplateDecl,
+	funcs map[string]interface{},
+	definedIn map[string]string,
+) ([]*TypeMutator, error) {
+	ret := []*TypeMutator{}
+	libs := map[string]string{}
+	declared := map[string]int{}
+	for _, t := range locals {
+		declared[t.GetSlugName()]++
+	}
+	for _, u := range uses {
+		lib, err := loader.LoadLibrary(u.Path)
+		if err != nil {
+			return nil, err
+		}
+		alias := u.Name
+		if alias == "" {
+			alias = path.Base(u.Path)
+		}
+		if p, ok := libs[alias]; ok {
+			return nil, fmt.Errorf("the libraries %q and %q are both named %q", p, u.Path, alias)
+		}
+		libs[alias] = u.Path
+		// the library is shared, work on copies.
+		for _, t := range lib.Templates {
+			decl := t.Clone()
+			decl.SetTokenValue(glanglexer.TplOpenToken, "<:")
+			decl.SetTokenValue(glanglexer.TplCloseToken, ":>")
+			for _, m := range decl.Methods {
+				m.SetTokenValue(glanglexer.TplOpenToken, "<:")
+				m.SetTokenValue(glanglexer.TplCloseToken, ":>")
+			}
+			for _, e := range decl.Extras {
+				e.SetTokenValue(glanglexer.TplOpenToken, "<:")
+				e.SetTokenValue(glanglexer.TplCloseToken, ":>")
+			}
+			ret = append(ret, &TypeMutator{Decl: decl, Name: alias + "." + decl.GetSlugName()})
+			declared[decl.GetSlugName()]++
+		}
+		// the funcs are not namespaced, a name is declared once.
+		origin := fmt.Sprintf("by the library %q", u.Path)
+		for _, d := range lib.Defines {
+			name := d.GetName()
+			if o, ok := definedIn[name]; ok && o != origin {
+				return nil, fmt.Errorf("the define %v of the library %q is also declared %v", name, u.Path, o)
+			}
+			definedIn[name] = origin
+			funcs[name] = stubFunc(d.String())
+		}
+	}
+	for _, m := range ret {
+		if name := m.Decl.GetSlugName(); declared[name] == 1 {
+			ret = append(ret, &TypeMutator{Decl: m.Decl, Name: name})
+		}
+	}
+	return ret, nil
+}
+
+// attachExtra attaches a template(XXX) declaration to the template XXX.
+func attachExtra(tpls []*glang.TemplateDecl, e *glang.TemplateExtraDecl) error {
+	for _, t := range tpls {
+		if t.GetSlugName() == e.GetOf() {
+			t.AddExtra(e)
+			return nil
+		}
+	}
+	return fmt.Errorf("template %v not found for the declaration at %v", e.GetOf(), e.Of.GetSpan())
+}
+
+type Tomate struct {
+	placeholders     []mutationExecuter
+	tplTypesMutators []*TypeMutator
+	implTplData      map[string]interface{}
+	err              error // the error of a placeholder, it stops the execution.
+}
+
+func (t *Tomate) getPlaceholder(name string) mutationExecuter {
+	for _, p := range t.placeholders {
+		if p.getName() == name {
+			return p
+		}
+	}
+	return nil
+}
+func (t *Tomate) GetResult(name string) string {
+	pl := t.getPlaceholder(name)
+
+	if pl != nil {
+		res, err := pl.execute(t.tplTypesMutators, t.implTplData)
+		if err != nil {
+			t.err = err
+			panic(err)
+		}
+		return res
+	}
+	return "not found"
+}
+
+type TemplateTplDot struct {
+	*glang.StructDecl
+	Interface *glang.InterfaceDecl // the target if it is an interface, its signatures are the .Methods.
+	Args      []interface{}
+	Params    map[string]interface{} // the args by name of the declared params.
+	Types     []*TypeArg             // the target, then the types given as args.
+	registry  *typeRegistry
+}
+
+// IsInterface returns true if the target is an interface.
+func (t *TemplateTplDot) IsInterface() bool {
+	return t.Interface != nil
+}
+
+// T returns the type i, starting at 1, or nil.
+func (t *TemplateTplDot) T(i int) *TypeArg {
+	if i < 1 || i > len(t.Types) {
+		return nil
+	}
+	return t.Types[i-1]
+}
+
+// T1 returns the target type, Todo in Map .Todo .User.
+func (t *TemplateTplDot) T1() *TypeArg { return t.T(1) }
+
+// T2 returns the first type given as an arg, User in Map .Todo .User.
+func (t *TemplateTplDot) T2() *TypeArg { return t.T(2) }
+
+// T3 returns the second type given as an arg.
+func (t *TemplateTplDot) T3() *TypeArg { return t.T(3) }
+
+// T4 returns the third type given as an arg.
+func (t *TemplateTplDot) T4() *TypeArg { return t.T(4) }
+
+// Props returns the fields of the target struct.
+func (t *TemplateTplDot) Props() []*glang.Field {
+	return t.StructDecl.GetFields()
+}
+
+// Field returns the field of the target struct with given name.
+func (t *TemplateTplDot) Field(name string) (*glang.Field, error) {
+	if f := t.StructDecl.GetField(name); f != nil {
+		return f, nil
+	}
+	return nil, fmt.Errorf("field %q not found in struct %v", name, t.GetName())
+}
+
+// Methods returns the methods of the target struct,
+// or the signatures of the target interface.
+func (t *TemplateTplDot) Methods() []*glang.MethodView {
+	ret := []*glang.MethodView{}
+	for _, m := range t.StructDecl.Methods {
+		ret = append(ret, glang.NewMethodView(m))
+	}
+	return ret
+}
+
+// MethodSet returns the method set of the target,
+// its methods of T and the methods promoted by its embedded fields.
+func (t *TemplateTplDot) MethodSet() ([]*glang.MethodView, error) {
+	return t.methodSet(false)
+}
+
+// PointerMethodSet returns the method set of a pointer to the target,
+// its methods of T and *T and the methods promoted by its embedded fields.
+func (t *TemplateTplDot) PointerMethodSet() ([]*glang.MethodView, error) {
+	return t.methodSet(true)
+}
+
+func (t *TemplateTplDot) methodSet(pointer bool) ([]*glang.MethodView, error) {
+	var decl interface{} = t.StructDecl
+	if t.Interface != nil {
+		decl = t.Interface
+	}
+	methods, err := t.registry.methodSet(decl, pointer)
+	if err != nil {
+		return nil, err
+	}
+	ret := []*glang.MethodView{}
+	for _, m := range methods {
+		ret = append(ret, glang.NewMethodView(m))
+	}
+	return ret, nil
+}
+
+// ArgType returns the declared type of the field named by the argument s,
+// or an error if the target has no such field.
+func (t *TemplateTplDot) ArgType(s interface{}) (string, error) {
+	if f := t.StructDecl.GetField(fmt.Sprint(s)); f != nil {
+		return f.Type.String(), nil
+	}
+	return "", fmt.Errorf("the target %v has no field %v", t.GetName(), s)
+}
+
+var plToken lexer.TokenType = -200
+
+func placeholderToken(name string, origin genericinterperter.Span) *genericinterperter.TokenWithPos {
+	tok := lexer.Token{
+		Type:  plToken,
+		Value: fmt.Sprintf("<:.GetResult \"%v\":>", name),
+	}
+	return genericinterperter.NewGeneratedToken(tok, origin)
+}
+
+func placeholdComments(T lexer.TokenType, src *glang.FileDecl, prefix string) []mutationExecuter {
+	ret := []mutationExecuter{}
+	for _, c := range src.FindAll(T) {
+		name := fmt.Sprintf("placeholder%v%v", prefix, len(ret))
+		m := NewPlaceholderMutation(name, c.GetTokens()[0])
+		ret = append(ret, m)
+		src.InsertAfter(c, m.PlaceholderDecl)
+		src.Remove(c)
+	}
+	return ret
+}
+
+type mutationExecuter interface {
+	execute(mutators []*TypeMutator, data interface{}) (string, error)
+	getName() string
+}
+
+type placeholderMutation struct {
+	OriginDecl      genericinterperter.Tokener
+	PlaceholderDecl *genericinterperter.TokenWithPos
+	Name            string
+}
+
+func (p *placeholderMutation) getName() string {
+	return p.Name
+}
+func (p *placeholderMutation) execute(mutators []*TypeMutator, data interface{}) (string, error) {
+	return p.OriginDecl.String(), nil
+}
+
+func NewPlaceholderMutation(name string, of genericinterperter.Tokener) *placeholderMutation {
+	return &placeholderMutation{
+		OriginDecl:      of,
+		PlaceholderDecl: placeholderToken(name, of.GetSpan()),
+		Name:            name,
+	}
+}
+
+type placeholderTypeMutation struct {
+	mutation        *ImplTypeMutation
+	PlaceholderDecl *genericinterperter.TokenWithPos
+	Name            string
+
+	// an implements is executed once, when its placeholder is met,
+	// or before, when another implements uses its type.
+	running bool
+	done    bool
+	res     string
+	err     error
+}
+
+func (p *placeholderTypeMutation) getName() string {
+	return p.Name
+}
+func (p *placeholderTypeMutation) execute(mutators []*TypeMutator, data interface{}) (string, error) {
+	if p.done {
+		return p.res, p.err
+	}
+	if p.running {
+		return "", fmt.Errorf("the implements %v depends on its own type, at %v",
+			p.mutation.Decl.GetName(), p.mutation.Decl.ImplementTemplate.GetSpan())
+	}
+	p.running = true
+	expr, err := p.mutation.mutate(mutators, data)
+	res := ""
+	if expr != nil {
+		res = expr.String()
+	}
+	p.running = false
+	p.done, p.res, p.err = true, res, err
+	return res, err
+}
+
+func NewPlaceholderTypeMutation(name string, of *glang.ImplementDecl, pkgFuncs map[string]interface{}, instances *Instances) *placeholderTypeMutation {
+	return &placeholderTypeMutation{
+		mutation:        &ImplTypeMutation{Decl: of, pkgFuncs: pkgFuncs, instances: instances},
+		PlaceholderDecl: placeholderToken(name, of.GetSpan()),
+		Name:            name,
+	}
+}
+
+func makeTplOfSource(name, src string, funcs map[string]interface{}) *template.Template {
+	t, err := template.New(name).Funcs(funcs).Delims("<:", ":>").Parse(src)
+	if err != nil {
+		// fmt.Println(src)
+		fErr := genericinterperter.NewStringTplSyntaxError(err, name, src)
+		fmt.Printf("%#v\n", fErr)
+		panic(fErr)
+	}
+	return t
+}
+
+func stubFunc(content string) func() error {
+	return func() error {
+		fmt.Println("template func content is")
+		fmt.Println(content)
+		return nil
+	}
+}
+
+type TypeMutator struct {
+	Decl  *glang.TemplateDecl
+	Name  string // name of the template in implements expressions, tpl.Name for a library template.
+	funcs map[string]interface{}
+
+	imports        []glang.ImportSpec // the imports of the gigo file the type is emitted in.
+	generic        *genericType       // the template written as a generic type, nil if it can not be.
+	genericChecked bool
+}
+
+func (t *TypeMutator) getTemplateStr() string {
+	tplContent := ""
+	decl := t.Decl.Clone()
+	// template XXXX(keys ...field) struct{} is written XXXX struct{}
+	decl.RemoveParams()
+	// the template declares a type like this
+	// template XXXX struct{}
+	// it is needed to replace the template keyword by a type.
+	// => type XXXX struct{}
+	if y := decl.GetToken(glanglexer.TemplateToken); y != nil {
+		// y.SetType(glanglexer.TypeToken) // not needed to update
+		y.SetValue("type")
+	}
+	tplContent += decl.String()
+	for _, m := range decl.Methods {
+		tplContent += m.String()
+		if m.GetModifier() != nil { // test if there is a front modifier like <range $m :=...>
+			tplContent += "<:end:>" // close the template expression, quick and dirty, but just works :)
+		}
+	}
+	// the type comes first, it is the type of the template.
+	for _, e := range decl.Extras {
+		tplContent += "\n\n" + e.Decl.String()
+	}
+	return tplContent
+}
+func (t *TypeMutator) execute(data interface{}) (string, error) {
+	name := t.Decl.GetName()
+	src := t.getTemplateStr()
+	tpl := makeTplOfSource(name, src, t.funcs)
+	var buf bytes.Buffer
+	err := tpl.Execute(&buf, data)
+	return buf.String(), err
+}
+
+// mutate returns the type generated for the target of arg, with its methods,
+// and the other declarations of the template, its funcs, consts, vars and types.
+func (t *TypeMutator) mutate(arg *TemplateTplDot) (*glang.StructDecl, []genericinterperter.Tokener, error) {
+	// the provided argument becomes the template root dot {{.}}
+	origin := arg.StructDecl
+	content, err := t.execute(arg)
+	if err == nil {
+		// we shall parse it
+		newFileDef, err := InterpretString(t.Decl.GetName(), content)
+		if err != nil {
+			return origin, nil, err
+		}
+		structs := newFileDef.FindStructsTypes()
+		if len(structs) == 0 {
+			return origin, nil, fmt.Errorf("the template %v does not generate a type for %v, at %v", t.Name, origin.GetName(), t.Decl.GetSpan())
+		}
+		newStruct := structs[0] // a type for a type
+
+		// should it be added to the current template data ?
+		// structTypes = append(structTypes, newStruct)
+		// note, it is expected the type gets added to the package repository.
+
+		// dont forget to attach its method.
+		methods := map[genericinterperter.Tokener]bool{}
+		for _, f := range newFileDef.FindFuncs() {
+			if f.IsMethod() && glang.NewTypeRef(f.GetReceiverType().String()).Base().Name() == newStruct.GetName() {
+				newStruct.AddMethod(f)
+				methods[f] = true
+			}
+		}
+		// everything else is emitted along the type.
+		var extras []genericinterperter.Tokener
+		for _, x := range newFileDef.Tokens {
+			if x == genericinterperter.Tokener(newStruct) || methods[x] {
+				continue
+			}
+			switch x.(type) {
+			case *glang.PackageDecl, *genericinterperter.TokenWithPos: // ws and comments.
+				continue
+			}
+			extras = append(extras, x)
+		}
+		return newStruct, extras, nil
+	}
+	return origin, nil, err
+}
+
+type ImplTypeMutation struct {
+	scope     genericinterperter.Expression
+	Decl      *glang.ImplementDecl
+	Res       []*glang.StructDecl                                // the types to emit, the cached ones are not.
+	Extras    map[*glang.StructDecl][]genericinterperter.Tokener // the other declarations of a type of Res.
+	Generics  map[*glang.StructDecl][]genericinterperter.Tokener // the declarations emitted instead of a type of Res, see Generics.
+	pkgFuncs  map[string]interface{}
+	instances *Instances
+	last      *glang.StructDecl // the result of the last mutation.
+	err       error             // the error of a mutation, it stops the execution.
+
+	lastInterface *glang.InterfaceDecl // the result of the last mutation, if it derived an interface.
+
+	types          *typeRegistry
+	final          *glang.ImplementDecl // the implements written as a struct, once mutated.
+	finalInterface *glang.InterfaceDecl // the interface declared by the implements, once mutated.
+	finalStruct    *glang.StructDecl
+}
+
+// resolved returns the type declared by the implements once it is mutated,
+// a struct with the methods declared on it, or an interface.
+func (t *ImplTypeMutation) resolved() (interface{}, error) {
+	if t.finalInterface != nil {
+		return t.finalInterface, nil
+	}
+	if t.finalStruct != nil {
+		return t.finalStruct, nil
+	}
+	if t.final == nil {
+		return nil, fmt.Errorf("the implements %v is not mutated", t.Decl.GetName())
+	}
+	i := t.final.Clone()
+	i.RemoveT(plToken) // the comments are not needed.
+	fileDef, err := InterpretString(t.Decl.GetName(), "package resolved\n\n"+i.String())
+	if err != nil {
+		return nil, err
+	}
+	structs := fileDef.FindStructsTypes()
+	if len(structs) == 0 {
+		return nil, fmt.Errorf("the implements %v is not a struct", t.Decl.GetName())
+	}
+	ret := structs[0]
+	for _, m := range t.Decl.Methods {
+		ret.AddMethod(m)
+	}
+	t.finalStruct = ret
+	return ret, nil
+}
+
+func (t *ImplTypeMutation) getMutationFunc(m *TypeMutator) func(target interface{}, args ...interface{}) (*glang.StructDecl, error) {
+	return func(target interface{}, args ...interface{}) (*glang.StructDecl, error) {
+		// the target of an implements is the type it declares.
+		if x, ok := target.(*glang.ImplementDecl); ok {
+			res, err := t.types.resolve(x)
+			if err != nil {
+				if t.err == nil {
+					t.err = err
+				}
+				return nil, t.err
+			}
+			target = res
+		}
+		// an implements given as an arg is the type it declares too.
+		for i, a := range args {
+			if x, ok := a.(*glang.ImplementDecl); ok {
+				res, err := t.types.resolve(x)
+				if err != nil {
+					if t.err == nil {
+						t.err = err
+					}
+					return nil, t.err
+				}
+				args[i] = res
+			}
+		}
+		// the target is a struct, an interface, or the name of a type, Map "string" .Todo.
+		origin, ok := target.(*glang.StructDecl)
+		intf, isIntf := target.(*glang.InterfaceDecl)
+		if !ok {
+			var err error
+			if isIntf {
+				origin, err = interfaceStruct(intf)
+			} else {
+				name, _ := target.(string)
+				origin, err = builtinStruct(name)
+			}
+			if err != nil {
+				t.err = fmt.Errorf("the target %#v of the template %v is not a type, at %v", target, m.Name, t.Decl.ImplementTemplate.GetSpan())
+				return nil, t.err
+			}
+		}
+		params, err := bindArgs(m, origin, args)
+		if err != nil {
+			// the error is reported at the call site.
+			t.err = fmt.Errorf("%v, at %v", err, t.Decl.ImplementTemplate.GetSpan())
+			return nil, t.err
+		}
+		key, desc := instanceKey(m, origin, args)
+		t.lastInterface = nil
+		if res := t.instances.get(key); res != nil {
+			t.last = res
+			return res, nil
+		}
+		arg := &TemplateTplDot{StructDecl: origin, Interface: intf, Args: args, Params: params, Types: typeArgs(m, target, args), registry: t.types}
+		res, extras, err := m.mutate(arg)
+		if err != nil {
+			return res, err
+		}
+		if err := t.instances.add(key, desc, res); err != nil {
+			t.err = err
+			return nil, err
+		}
+		t.Res = append(t.Res, res)
+		// the generic type is expanded if its name is taken by a type of the package.
+		if g := m.genericType(); g != nil && t.instances.Backend == Generics && !t.types.declares(g.Name) {
+			decls, err := t.instances.genericDecls(g, res.GetName(), origin.GetName())
+			if err != nil {
+				t.err = fmt.Errorf("%v, at %v", err, t.Decl.ImplementTemplate.GetSpan())
+				return nil, t.err
+			}
+			if t.Generics == nil {
+				t.Generics = map[*glang.StructDecl][]genericinterperter.Tokener{}
+			}
+			t.Generics[res] = decls
+		}
+		if len(extras) > 0 {
+			if t.Extras == nil {
+				t.Extras = map[*glang.StructDecl][]genericinterperter.Tokener{}
+			}
+			t.Extras[res] = extras
+		}
+		t.last = res
+		return res, nil
+	}
+}
+
+func (t *ImplTypeMutation) mutate(mutators []*TypeMutator, data interface{}) (*glang.StrDecl, error) {
+	// in a decl like implement<X Y Z>
+	// X Y Z are func template of a template string "X Y Z"
+	funcs := map[string]interface{}{}
+	for k, v := range t.pkgFuncs {
+		funcs[k] = v
+	}
+	funcs[interfaceFuncName] = t.getInterfaceFunc()
+	for _, m := range mutators {
+		funcs[strings.Replace(m.Name, ".", "__", -1)] = t.getMutationFunc(m)
+	}
+	// every expression of implement<:A .T, B .T> is executed on its own,
+	// the last type it produces is embedded.
+	var embeds []*glang.StructDecl
+	var iface *glang.InterfaceDecl
+	for _, c := range t.Decl.Chains {
+		// implements<:.Todo | Slice | Mutexed> is executed as Mutexed (Slice .Todo)
+		tplContent := "<:" + c.Expr() + ":>"
+		for _, m := range mutators {
+			if strings.Contains(m.Name, ".") {
+				// tpl.Name is not a valid func name, it is renamed tpl__Name.
+				re := regexp.MustCompile(`(^|[^\w.$])` + regexp.QuoteMeta(m.Name) + `\b`)
+				tplContent = re.ReplaceAllString(tplContent, "${1}"+strings.Replace(m.Name, ".", "__", -1))
+			}
+		}
+		t.last = nil
+		t.lastInterface = nil
+		tpl := makeTplOfSource("gigo", tplContent, funcs)
+		if err := tpl.Execute(ioutil.Discard, data); err != nil {
+			if t.err != nil {
+				return nil, t.err
+			}
+			fmt.Println(tplContent)
+			return nil, err
+		}
+		// once the template "X Y Z" invoked => new struct type is added to t.Res,
+		// unless it was already generated.
+		if t.last != nil {
+			embeds = append(embeds, t.last)
+		}
+		// implements<:Interface .Todos true> declares an interface.
+		if t.lastInterface != nil {
+			if len(t.Decl.Chains) > 1 {
+				return nil, fmt.Errorf("the interface %v derived by %v must be the only expression of the implements, at %v",
+					t.lastInterface.GetName(), c, t.Decl.ImplementTemplate.GetSpan())
+			}
+			if len(t.Decl.Methods) > 0 {
+				return nil, fmt.Errorf("the interface %v can not declare the method %v, at %v",
+					t.Decl.GetName(), t.Decl.Methods[0].GetName(), t.Decl.ImplementTemplate.GetSpan())
+			}
+			iface = t.lastInterface
+		}
+	}
+	if err := t.checkCollisions(embeds); err != nil {
+		return nil, err
+	}
+	if iface != nil {
+		return t.interfaceDecl(iface), nil
+	}
+
+	// finalize the implements instruction into a regular struct
+	// it becomes regular go code.
+	// from => type xxx impements<y u i>{}
+	// to => type xxx struct{}
+	i := t.Decl.Clone()
+	i.SetTokenValue(glanglexer.ImplementsToken, "struct")
+	i.RemoveT(glanglexer.TplOpenToken) // get ride of the template mutations
+
+	strDecl := &glang.StrDecl{}
+
+	// the blank lines before the declaration now separate the generated types.
+	for len(i.Tokens) > 0 && (i.Tokens[0].GetType() == glanglexer.NlToken || i.Tokens[0].GetType() == genericlexer.WsToken) {
+		strDecl.AddExpr(i.Tokens[0])
+		i.RemoveAt(0)
+	}
+
+	if len(embeds) > 0 {
+		// generated tokens refer to the implements declaration.
+		origin := i.GetSpan()
+
+		// define the last genrated type of each expression as an underlying type of i
+		for k, e := range embeds {
+			tok := genericinterperter.NewGeneratedToken(lexer.Token{Type: genericlexer.WordToken, Value: e.GetName()}, origin)
+			nl := genericinterperter.NewGeneratedToken(lexer.Token{Type: glanglexer.NlToken, Value: "\n"}, origin)
+			ws := genericinterperter.NewGeneratedToken(lexer.Token{Type: genericlexer.WsToken, Value: "\t"}, origin)
+
+			ID := glang.NewExpressionDecl()
+			name := glang.NewIdentifierDecl()
+			name.AddExpr(tok)
+			ID.AddExpr(name)
+			i.GetBlock().Underlying = append(i.GetBlock().Underlying, ID)
+			i.GetBlock().InsertAt(1+k*3, nl)
+			i.GetBlock().InsertAt(2+k*3, ws)
+			i.GetBlock().InsertAt(3+k*3, ID)
+		}
+
+		// add every generated types and all of their methods to the string decl
+		for _, r := range t.Res {
+			// the type is an instantiation of a generic type.
+			if decls, ok := t.Generics[r]; ok {
+				for _, x := range decls {
+					strDecl.AddExpr(x)
+					nl := genericinterperter.NewGeneratedToken(lexer.Token{Type: glanglexer.NlToken, Value: "\n"}, r.GetSpan())
+					strDecl.AddExpr(nl)
+				}
+				continue
+			}
+			strDecl.AddExprs(r.Tokens)
+			nl := genericinterperter.NewGeneratedToken(lexer.Token{Type: glanglexer.NlToken, Value: "\n"}, r.GetSpan())
+			strDecl.AddExpr(nl)
+			for _, m := range r.Methods {
+				strDecl.AddExprs(m.GetTokens())
+				nl := genericinterperter.NewGeneratedToken(lexer.Token{Type: glanglexer.NlToken, Value: "\n"}, r.GetSpan())
+				strDecl.AddExpr(nl)
+			}
+			for _, x := range t.Extras[r] {
+				strDecl.AddExpr(x)
+				nl := genericinterperter.NewGeneratedToken(lexer.Token{Type: glanglexer.NlToken, Value: "\n"}, r.GetSpan())
+				strDecl.AddExpr(nl)
+			}
+		}
+	}
+	// finally add the original modified I struct to the string decl
+	strDecl.AddExpr(i)
+	t.final = i
+
+	return strDecl, nil
+}
+
+// interfaceDecl returns the declaration of the interface derived by the implements,
+// it is named after the implements.
+func (t *ImplTypeMutation) interfaceDecl(iface *glang.InterfaceDecl) *glang.StrDecl {
+	strDecl := &glang.StrDecl{}
+
+	i := t.Decl.Clone()
+	for len(i.Tokens) > 0 && (i.Tokens[0].GetType() == glanglexer.NlToken || i.Tokens[0].GetType() == genericlexer.WsToken) {
+		strDecl.AddExpr(i.Tokens[0])
+		i.RemoveAt(0)
+	}
+
+	iface = iface.Clone()
+	iface.Name.SetValue(t.Decl.GetName())
+	strDecl.AddExpr(iface)
+	t.finalInterface = iface
+	return strDecl
+}
+
+// checkCollisions returns an error if two embedded types promote
+// a method, or a method and a field, with the same name at the same depth,
+// unless the implementing type declares it.
+// The selectors promoted by the embedded types of the embedded types are checked, see selectors.
+func (t *ImplTypeMutation) checkCollisions(embeds []*glang.StructDecl) error {
+	declared := map[string]bool{}
+	for _, m := range t.Decl.Methods {
+		declared[m.GetName()] = true
+	}
+	if b := t.Decl.GetBlock(); b != nil {
+		for _, f := range b.GetFields() {
+			declared[f.Name] = true
+		}
+	}
+	type promotion struct {
+		selector
+		by string
+	}
+	promotedBy := map[string]promotion{}
+	for _, e := range embeds {
+		selectors, err := t.types.selectors(e)
+		if err != nil {
+			return err
+		}
+		for _, s := range selectors {
+			if declared[s.Name] {
+				continue
+			}
+			// two fields are ambiguous only where they are used.
+			other, ok := promotedBy[s.Name]
+			if ok && other.by != e.GetName() && other.Depth == s.Depth && (s.Kind == "method" || other.Kind == "method") {
+				kind := s.Kind
+				if other.Kind != s.Kind {
+					kind = "selector"
+				}
+				return fmt.Errorf(
+					"ambiguous %v %v of %v at %v, it is promoted by %v and %v, declare it on %v",
+					kind, s.Name, t.Decl.GetName(), t.Decl.ImplementTemplate.GetSpan(), other.by, e.GetName(), t.Decl.GetName(),
+				)
+			}
+			if !ok || s.Depth < other.Depth {
+				promotedBy[s.Name] = promotion{s, e.GetName()}
+			}
+		}
+	}
+	return nil
+}
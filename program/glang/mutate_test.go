@@ -0,0 +1,1005 @@
+package program
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	glang "github.com/mh-cbon/gigo/struct/glang"
+)
+
+func TestMutateChain(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gigo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := `package tomate
+
+type Todo struct {
+  Name string
+}
+
+type Todos implements<:.Todo | Slice | Nope "Name"> {}
+
+template <:.Name>Slice struct {
+  items []<:.Name>
+}
+`
+	writeFiles(t, dir, map[string]string{
+		"nope.gigo.go":   src,
+		"tomate.gigo.go": strings.Replace(src, ` | Nope "Name"`, "", 1),
+		"arity.gigo.go": strings.Replace(strings.Replace(src, ` | Nope "Name"`, "", 1),
+			"Slice struct", "Slice(sorted bool) struct", 1),
+	})
+
+	fileDef, err := InterpretFile(filepath.Join(dir, "nope.gigo.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = Mutate(fileDef)
+	if err == nil {
+		t.Fatal("expected an error for the unknown template Nope")
+	}
+	if want := `unknown template "Nope" in the implements pipeline at ` + filepath.Join(dir, "nope.gigo.go") + ":7:40-7:51"; err.Error() != want {
+		t.Errorf("unexpected error wanted=%q, got=%q", want, err.Error())
+	}
+
+	fileDef, err = InterpretFile(filepath.Join(dir, "arity.gigo.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = Mutate(fileDef)
+	if err == nil {
+		t.Fatal("expected an error for the missing arg of Slice")
+	}
+	if want := `wrong number of args for the template Slice(sorted bool), wanted 1, got 0 in the implements pipeline at ` + filepath.Join(dir, "arity.gigo.go") + ":7:32-7:37"; err.Error() != want {
+		t.Errorf("unexpected error wanted=%q, got=%q", want, err.Error())
+	}
+
+	fileDef, err = InterpretFile(filepath.Join(dir, "tomate.gigo.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := Mutate(fileDef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "type Todos struct {\n\tTodoSlice}"; !strings.Contains(res.String(), want) {
+		t.Errorf("unexpected result, %q not found in\n%v", want, res.String())
+	}
+}
+
+func TestMutateCollisions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gigo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := `package tomate
+
+type Todo struct {
+  Name string
+}
+
+type Todos implements<:IMPL> {}
+
+template <:.Name>A struct {}
+
+func (s <:.Name>A) Len() int { return 0 }
+
+template <:.Name>B struct {}
+
+func (s <:.Name>B) Len() int { return 1 }
+
+template <:.Name>F struct {
+  Len int
+  items []<:.Name>
+}
+
+template <:.Name>G struct {
+  items []<:.Name>
+}
+
+type Counter struct {}
+
+func (c *Counter) Count() int { return 0 }
+
+type Sizer struct {}
+
+func (s Sizer) Count() int { return 0 }
+
+template <:.Name>C struct {
+  Counter
+}
+
+template <:.Name>D struct {
+  *Sizer
+}
+`
+	tests := []struct {
+		impl string
+		err  string
+	}{
+		{impl: "A .Todo, B .Todo", err: "ambiguous method Len of Todos at %v:7:22-7:41, it is promoted by TodoA and TodoB, declare it on Todos"},
+		// a method and a field.
+		{impl: "A .Todo, F .Todo", err: "ambiguous selector Len of Todos at %v:7:22-7:41, it is promoted by TodoA and TodoF, declare it on Todos"},
+		// the methods promoted by the embedded types.
+		{impl: "C .Todo, D .Todo", err: "ambiguous method Count of Todos at %v:7:22-7:41, it is promoted by TodoC and TodoD, declare it on Todos"},
+		// a shallower method is not ambiguous.
+		{impl: "A .Todo, C .Todo"},
+		// two fields are not ambiguous until they are used.
+		{impl: "F .Todo, G .Todo"},
+	}
+	for i, test := range tests {
+		name := filepath.Join(dir, fmt.Sprintf("t%v.gigo.go", i))
+		writeFiles(t, dir, map[string]string{
+			filepath.Base(name): strings.Replace(src, "IMPL", test.impl, 1),
+		})
+		fileDef, err := InterpretFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = Mutate(fileDef)
+		if test.err == "" {
+			if err != nil {
+				t.Errorf("%q: unexpected error %v", test.impl, err)
+			}
+			continue
+		}
+		if want := fmt.Sprintf(test.err, name); err == nil || err.Error() != want {
+			t.Errorf("%q: unexpected error wanted=%q, got=%v", test.impl, want, err)
+		}
+	}
+
+	writeFiles(t, dir, map[string]string{
+		"resolved.gigo.go": strings.Replace(src, "IMPL", "A .Todo, B .Todo", 1) + "\nfunc (t Todos) Len() int { return 2 }\n",
+	})
+	fileDef, err := InterpretFile(filepath.Join(dir, "resolved.gigo.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := Mutate(fileDef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "type Todos struct {\n\tTodoA\n\tTodoB}"; !strings.Contains(res.String(), want) {
+		t.Errorf("unexpected result, %q not found in\n%v", want, res.String())
+	}
+}
+
+func TestMutatePackage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gigo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Todo and Slice are declared by a, the other files use them,
+	// the method of Slice is declared by b.
+	writeFiles(t, dir, map[string]string{
+		"a.gigo.go": `package tomate
+
+type Todos implements<:Slice .Todo "Name"> {}
+
+type Todo struct {
+  Name string
+}
+
+template <:.Name>Slice struct {
+  items []<:.Name>
+}
+`,
+		"b.gigo.go": `package tomate
+
+type Tasks implements<:Slice .Todo "Name"> {}
+
+func (s *<:.Name>Slice) Len() int { return len(s.items) }
+`,
+		"c.gigo.go": "package tomate\n\ntype Items implements<:Slice .Todo \"ID\"> {}\n",
+		"d.gigo.go": "package tomate\n\ntype Todo struct {}\n",
+	})
+	var files []*glang.FileDecl
+	for _, name := range []string{"a.gigo.go", "b.gigo.go"} {
+		f, err := InterpretFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, f)
+	}
+	res, err := MutatePackage(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(res[0].String(), "type TodoSlice struct"); got != 1 {
+		t.Errorf("unexpected TodoSlice count in a wanted=%v, got=%v\n%v", 1, got, res[0])
+	}
+	if got := strings.Count(res[1].String(), "type TodoSlice struct"); got != 0 {
+		t.Errorf("unexpected TodoSlice count in b wanted=%v, got=%v\n%v", 0, got, res[1])
+	}
+	if want := "type Tasks struct {\n\tTodoSlice}"; !strings.Contains(res[1].String(), want) {
+		t.Errorf("unexpected result, %q not found in\n%v", want, res[1])
+	}
+	if want := "func (s *TodoSlice) Len() int"; !strings.Contains(res[0].String(), want) {
+		t.Errorf("unexpected result, %q not found in\n%v", want, res[0])
+	}
+
+	c, err := InterpretFile(filepath.Join(dir, "c.gigo.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = MutatePackage([]*glang.FileDecl{files[0], c})
+	if err == nil {
+		t.Fatal("expected an error for the type TodoSlice generated twice")
+	}
+	if want := `the type TodoSlice is generated by Slice Todo "Name" and by Slice Todo "ID"`; err.Error() != want {
+		t.Errorf("unexpected error wanted=%q, got=%q", want, err.Error())
+	}
+
+	d, err := InterpretFile(filepath.Join(dir, "d.gigo.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = MutatePackage([]*glang.FileDecl{files[0], d})
+	if err == nil {
+		t.Fatal("expected an error for the type Todo declared twice")
+	}
+	if want := "the type Todo is declared by " + filepath.Join(dir, "a.gigo.go") + " and by " + filepath.Join(dir, "d.gigo.go"); err.Error() != want {
+		t.Errorf("unexpected error wanted=%q, got=%q", want, err.Error())
+	}
+}
+
+func TestMutateLoader(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gigo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeFiles(t, dir, map[string]string{
+		"go.mod":             "module example.com/tomate\n",
+		"tpls/slice.gigo.go": "package tpls\n\ntemplate <:.Name>Slice struct {\n  items []<:.Name>\n}\n",
+		"a.gigo.go":          "package tomate\n\n//gigo:use \"./tpls\"\n\ntype Todo struct {}\n\ntype Todos implements<:Slice .Todo> {}\n",
+		"b.gigo.go":          "package tomate\n\n//gigo:use \"./tpls\"\n\ntype Task struct {}\n\ntype Tasks implements<:Slice .Task> {}\n",
+	})
+	// the files mutated with the same instances load the library once.
+	instances := NewInstances()
+	var loader *Loader
+	for _, name := range []string{"a.gigo.go", "b.gigo.go"} {
+		fileDef, err := InterpretFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := MutateWith(fileDef, instances); err != nil {
+			t.Fatal(err)
+		}
+		if loader == nil {
+			loader = instances.Loader
+		}
+		if instances.Loader != loader {
+			t.Errorf("%v: unexpected loader wanted=%p, got=%p", name, loader, instances.Loader)
+		}
+	}
+	if loader == nil {
+		t.Fatal("unexpected nil loader")
+	}
+	if got := len(loader.libraries); got != 1 {
+		t.Errorf("unexpected libraries len wanted=%v, got=%v", 1, got)
+	}
+}
+
+func TestMutateDefines(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gigo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeFiles(t, dir, map[string]string{
+		"go.mod":          "module example.com/tomate\n",
+		"a/slice.gigo.go": "package a\n\n<:define> func Upper(s string) string { return s }\n\ntemplate <:.Name>Slice struct {\n  items []<:.Name>\n}\n",
+		"b/list.gigo.go":  "package b\n\n<:define> func Upper(s string) string { return s }\n\ntemplate <:.Name>List struct {\n  items []<:.Name>\n}\n",
+		"one.gigo.go":     "package tomate\n\n//gigo:use \"./a\"\n\ntype Todo struct {}\n\ntype Todos implements<:Slice .Todo> {}\n",
+		"two.gigo.go":     "package tomate\n\n//gigo:use \"./a\"\n\ntype Task struct {}\n\ntype Tasks implements<:Slice .Task> {}\n",
+		"libs.gigo.go":    "package tomate\n\n//gigo:use \"./a\"\n//gigo:use \"./b\"\n\ntype Todo struct {}\n\ntype Todos implements<:Slice .Todo> {}\n",
+		"local.gigo.go":   "package tomate\n\n//gigo:use \"./a\"\n\n<:define> func Upper(s string) string { return s }\n\ntype Todo struct {}\n\ntype Todos implements<:Slice .Todo> {}\n",
+	})
+
+	tests := []struct {
+		files []string
+		err   string
+	}{
+		// a library used by several files declares its funcs once.
+		{files: []string{"one.gigo.go", "two.gigo.go"}},
+		{files: []string{"libs.gigo.go"}, err: `the define Upper of the library "./b" is also declared by the library "./a"`},
+		{files: []string{"local.gigo.go"}, err: `the define Upper of the library "./a" is also declared at `},
+	}
+	for _, test := range tests {
+		fileDefs := []*glang.FileDecl{}
+		for _, name := range test.files {
+			fileDef, err := InterpretFile(filepath.Join(dir, name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			fileDefs = append(fileDefs, fileDef)
+		}
+		_, err := MutatePackage(fileDefs)
+		if test.err == "" {
+			if err != nil {
+				t.Errorf("%v: unexpected error %v", test.files, err)
+			}
+		} else if err == nil || !strings.HasPrefix(err.Error(), test.err) {
+			t.Errorf("%v: unexpected error wanted=%q, got=%v", test.files, test.err, err)
+		}
+	}
+}
+
+func TestMutateParams(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gigo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := `package tomate
+
+type Todo struct {
+  Name string
+}
+
+type Todos implements<:Slice .Todo ARGS> {}
+
+template <:.Name>Slice(PARAMS) struct {
+  items []<:.Name>
+}
+`
+	tests := []struct {
+		params string
+		args   string
+		err    string
+	}{
+		{args: `true "Name"`},
+		{args: `false`},
+		{args: ``, err: "wrong number of args for the template Slice(sorted bool, keys ...field), wanted at least 1, got 0"},
+		{args: `"Name"`, err: `the arg "Name" of the param sorted of the template Slice is not a bool`},
+		{args: `true "Nope"`, err: `the arg "Nope" of the param keys of the template Slice is not a field of Todo`},
+		{args: `true 1`, err: `the arg 1 of the param keys of the template Slice is not a field`},
+		{params: "keys ...field, sorted bool", args: `"Name" "Name" true`},
+		{params: "keys ...field, sorted bool", args: `true`},
+		{params: "keys ...field, sorted bool", args: `"Name"`, err: `the arg "Name" of the param sorted of the template Slice is not a bool`},
+		{params: "keys ...field, sorted bool", args: `"Nope" true`, err: `the arg "Nope" of the param keys of the template Slice is not a field of Todo`},
+		{params: "a int, keys ...field, sorted bool", args: `1 "Name" true`},
+		{params: "a int, keys ...field, sorted bool", args: `1`, err: "wrong number of args for the template Slice(a int, keys ...field, sorted bool), wanted at least 2, got 1"},
+	}
+	for i, test := range tests {
+		params := test.params
+		if params == "" {
+			params = "sorted bool, keys ...field"
+		}
+		name := filepath.Join(dir, fmt.Sprintf("t%v.gigo.go", i))
+		writeFiles(t, dir, map[string]string{
+			filepath.Base(name): strings.Replace(strings.Replace(src, "PARAMS", params, 1), " ARGS", " "+test.args, 1),
+		})
+		fileDef, err := InterpretFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = Mutate(fileDef)
+		if test.err == "" {
+			if err != nil {
+				t.Errorf("%q: unexpected error %v", test.args, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("%q: expected an error %q", test.args, test.err)
+		} else if !strings.HasPrefix(err.Error(), test.err+", at "+name+":7:22") {
+			t.Errorf("%q: unexpected error wanted=%q, got=%q", test.args, test.err, err.Error())
+		}
+	}
+
+	writeFiles(t, dir, map[string]string{
+		"unknown.gigo.go": strings.Replace(strings.Replace(src, "PARAMS", "sorted boolean, keys ...field", 1), " ARGS", "", 1),
+	})
+	fileDef, err := InterpretFile(filepath.Join(dir, "unknown.gigo.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = Mutate(fileDef)
+	if want := "unknown type of the param sorted of the template Slice at "; err == nil || !strings.HasPrefix(err.Error(), want) {
+		t.Errorf("unexpected error wanted=%q, got=%v", want, err)
+	}
+
+	writeFiles(t, dir, map[string]string{
+		"variadics.gigo.go": strings.Replace(strings.Replace(src, "PARAMS", "keys ...field, names ...string", 1), " ARGS", "", 1),
+	})
+	fileDef, err = InterpretFile(filepath.Join(dir, "variadics.gigo.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = Mutate(fileDef)
+	if want := "the params keys and names of the template Slice are both variadic at "; err == nil || !strings.HasPrefix(err.Error(), want) {
+		t.Errorf("unexpected error wanted=%q, got=%v", want, err)
+	}
+
+	// the args of a template without params are not checked, ArgType is.
+	writeFiles(t, dir, map[string]string{
+		"argtype.gigo.go": `package tomate
+
+type Todo struct {
+  Name string
+}
+
+type Todos implements<:Index .Todo "Nope"> {}
+
+template <:.Name>Index struct {}
+
+<:range $a := .Args> func (s <:$.Name>Index) By<:$a>(v <:$.ArgType $a>) {}
+`,
+	})
+	fileDef, err = InterpretFile(filepath.Join(dir, "argtype.gigo.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = Mutate(fileDef)
+	if want := "error calling ArgType: the target Todo has no field Nope"; err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("unexpected error wanted=%q, got=%v", want, err)
+	}
+}
+
+func TestMutateTypes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gigo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := `package tomate
+
+type Todo struct {
+  Name string
+}
+
+type Todos implements<:ARGS> {}
+
+template <:.T1.Title><:.T2.Title>Map struct {
+  items map[<:.T1>]<:.T2>
+}
+
+template <:.Name>Pair(second Type) struct {
+  A <:.T1>
+  B <:.Params.second>
+}
+`
+	tests := []struct {
+		args string
+		want string
+		err  string
+	}{
+		{args: `Map "string" .Todo`, want: "type StringTodoMap struct {\n  items map[string]Todo\n}"},
+		{args: `Map .Todo .Todo`, want: "type TodoTodoMap struct {\n  items map[Todo]Todo\n}"},
+		{args: `Pair .Todo "[]byte"`, want: "type TodoPair struct {\n  A Todo\n  B []byte\n}"},
+		{args: `Pair .Todo .Todo`, want: "type TodoPair struct {\n  A Todo\n  B Todo\n}"},
+		{args: `Pair .Todo "[]"`, err: `the arg "[]" of the param second of the template Pair is not a Type`},
+		{args: `Map "[]byte" .Todo`, err: `the target "[]byte" of the template Map is not a type`},
+	}
+	for i, test := range tests {
+		name := filepath.Join(dir, fmt.Sprintf("t%v.gigo.go", i))
+		writeFiles(t, dir, map[string]string{
+			filepath.Base(name): strings.Replace(src, "ARGS", test.args, 1),
+		})
+		fileDef, err := InterpretFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := Mutate(fileDef)
+		if test.err == "" {
+			if err != nil {
+				t.Errorf("%q: unexpected error %v", test.args, err)
+			} else if !strings.Contains(res.String(), test.want) {
+				t.Errorf("%q: unexpected result, %q not found in\n%v", test.args, test.want, res)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("%q: expected an error %q", test.args, test.err)
+		} else if !strings.HasPrefix(err.Error(), test.err+", at "+name+":7:22") {
+			t.Errorf("%q: unexpected error wanted=%q, got=%q", test.args, test.err, err.Error())
+		}
+	}
+}
+
+func TestMutateExtras(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gigo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := `package tomate
+
+type Todo struct {
+  Name string
+}
+
+type Todos implements<:Slice .Todo> {}
+
+type MoreTodos implements<:Slice .Todo> {}
+
+template <:.Name>Slice struct {
+  items []<:.Name>
+}
+
+template(OF) func New<:.Name>Slice() *<:.Name>Slice {
+  return &<:.Name>Slice{}
+}
+`
+	writeFiles(t, dir, map[string]string{
+		"ok.gigo.go":      strings.Replace(src, "OF", "Slice", 1),
+		"unknown.gigo.go": strings.Replace(src, "OF", "List", 1),
+	})
+
+	fileDef, err := InterpretFile(filepath.Join(dir, "ok.gigo.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := Mutate(fileDef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "func NewTodoSlice() *TodoSlice {"
+	if got := strings.Count(res.String(), want); got != 1 {
+		t.Errorf("unexpected count of %q wanted=%v, got=%v in\n%v", want, 1, got, res)
+	}
+
+	name := filepath.Join(dir, "unknown.gigo.go")
+	fileDef, err = InterpretFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = Mutate(fileDef)
+	wantErr := "template List not found for the declaration at " + name + ":15:10"
+	if err == nil || !strings.HasPrefix(err.Error(), wantErr) {
+		t.Errorf("unexpected error wanted=%q, got=%v", wantErr, err)
+	}
+}
+
+func TestMutateInvalidTemplate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gigo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// the generated method does not parse, func (s TodoSlice) (Len() int {}.
+	src := `package tomate
+
+type Todo struct {}
+
+type Todos implements<:Slice .Todo> {}
+
+template <:.Name>Slice struct {}
+
+func (s <:.Name>Slice) <:"(">Len() int {}
+`
+	name := filepath.Join(dir, "t.gigo.go")
+	writeFiles(t, dir, map[string]string{"t.gigo.go": src})
+	fileDef, err := InterpretFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = Mutate(fileDef)
+	want := "error calling Slice: in <noname> unexpected token at line 5:30"
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("unexpected error wanted=%q, got=%v", want, err)
+	}
+}
+
+func TestMutateInterfaces(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gigo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeFiles(t, dir, map[string]string{
+		"go.mod": "module example.com/tomate\n",
+		"store/store.go": `package store
+
+type Store interface {
+	Get(id int) (string, error)
+	Len() int
+}
+`,
+		"main.gigo.go": `package main
+
+import "example.com/tomate/store"
+
+type Reader interface {
+  Read(p []byte) (n int, err error)
+}
+
+type Readers implements<:Kind .Reader> {}
+
+type Stores implements<:Kind store.Store> {}
+
+type Todos implements<:Kind .Todo> {}
+
+type Todo struct {}
+
+template <:.Name>Kind struct {
+  target <:.GetQualifiedName>
+}
+
+<:range $m := .Methods> func (k <:$.Name>Kind) <:$m.Name>Of<:if $.IsInterface>Interface<:else>Struct<:end>(<:$m.ParamsDecl>) <:$m.ResultsDecl> {
+  panic("")
+}
+`,
+	})
+	fileDef, err := InterpretFile(filepath.Join(dir, "main.gigo.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := Mutate(fileDef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"type ReaderKind struct {\n  target Reader\n}",
+		"func (k ReaderKind) ReadOfInterface(p []byte) (int, error) {",
+		"type StoreKind struct {\n  target store.Store\n}",
+		"func (k StoreKind) GetOfInterface(id int) (string, error) {",
+		"func (k StoreKind) LenOfInterface() int {",
+		"type TodoKind struct {\n  target Todo\n}",
+	} {
+		if !strings.Contains(res.String(), want) {
+			t.Errorf("unexpected result, %q not found in\n%v", want, res)
+		}
+	}
+}
+
+func TestMutateInterface(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gigo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := `package tomate
+
+type Todo struct {}
+
+func (t Todo) Name() string { return "" }
+func (t *Todo) SetName(n string) {}
+func (t *Todo) reset() {}
+func (t *Todo) Done(ok bool) error { return nil }
+
+type TodoAPI implements<:ARGS> {}
+`
+	tests := []struct {
+		args string
+		want string
+		err  string
+	}{
+		{args: `Interface .Todo false`, want: "type TodoAPI interface {\n\tName() string\n\tSetName(n string) \n\treset() \n\tDone(ok bool) error\n}"},
+		{args: `Interface .Todo true`, want: "type TodoAPI interface {\n\tName() string\n\tSetName(n string) \n\tDone(ok bool) error\n}"},
+		{args: `Interface .Todo true "*Name" "reset"`, want: "type TodoAPI interface {\n\tName() string\n\tSetName(n string) \n}"},
+		{args: `.Todo | Interface false "re*"`, want: "type TodoAPI interface {\n\treset() \n}"},
+		{args: `Interface .Todo true "["`, err: `invalid pattern "[" of the template Interface: syntax error in pattern, at `},
+		{args: `Interface "string" true`, err: `the target "string" of the template Interface has no methods, at `},
+		{args: `Interface .Todo true, Interface .Todo false`, err: "the interface TodoInterface derived by Interface .Todo true must be the only expression of the implements, at "},
+	}
+	for i, test := range tests {
+		name := filepath.Join(dir, fmt.Sprintf("t%v.gigo.go", i))
+		writeFiles(t, dir, map[string]string{
+			filepath.Base(name): strings.Replace(src, "ARGS", test.args, 1),
+		})
+		fileDef, err := InterpretFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := Mutate(fileDef)
+		if test.err == "" {
+			if err != nil {
+				t.Errorf("%q: unexpected error %v", test.args, err)
+			} else if !strings.Contains(res.String(), test.want) {
+				t.Errorf("%q: unexpected result, %q not found in\n%v", test.args, test.want, res)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("%q: expected an error %q", test.args, test.err)
+		} else if !strings.HasPrefix(err.Error(), test.err+name+":10:") {
+			t.Errorf("%q: unexpected error wanted=%q, got=%q", test.args, test.err, err.Error())
+		}
+	}
+}
+
+func TestMutateMethodSet(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gigo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := `package tomate
+
+import (
+  "sync"
+  "example.com/tomate/store"
+)
+
+type A struct {}
+func (a A) Value() {}
+func (a *A) Pointer() {}
+func (a A) Shadowed() {}
+func (a A) Ambiguous() {}
+
+type B struct {}
+func (b B) Ambiguous() {}
+func (b *B) Deep() {}
+
+type C struct {
+  *B
+}
+func (c C) Ambiguous() {}
+
+type Reader interface {
+  Read() int
+}
+
+type ReadCloser interface {
+  Reader
+  Close() error
+}
+
+type T struct {
+  A
+  C
+  ReadCloser
+  Shadowed int
+}
+func (t T) Own() {}
+
+type G struct {}
+func (g G) Diamond() {}
+func (g G) Shallow() {}
+
+type E struct { G }
+type F struct { G }
+func (f F) Shallow() {}
+
+type D struct {
+  E
+  F
+}
+
+type I struct {
+  *store.Item
+}
+
+type J struct {
+  sync.Mutex
+}
+
+type Ts implements<:Names .TARGET> {}
+
+template <:.Name>Names struct {}
+
+func (n <:.Name>Names) Value() []string {
+  return []string{<:range .MethodSet><:quote .Name>, <:end>}
+}
+
+func (n <:.Name>Names) Pointer() []string {
+  return []string{<:range .PointerMethodSet><:quote .Name>, <:end>}
+}
+`
+	tests := []struct {
+		target  string
+		value   string
+		pointer string
+		err     string
+	}{
+		{target: "T",
+			value:   `"Own", "Value", "Close", "Deep", "Read", `,
+			pointer: `"Own", "Value", "Pointer", "Close", "Deep", "Read", `},
+		{target: "A",
+			value:   `"Value", "Shadowed", "Ambiguous", `,
+			pointer: `"Value", "Pointer", "Shadowed", "Ambiguous", `},
+		{target: "ReadCloser",
+			value:   `"Close", "Read", `,
+			pointer: `"Close", "Read", `},
+		// D.E.G.Diamond and D.F.G.Diamond are ambiguous, D.F.Shallow is shallower.
+		{target: "D",
+			value:   `"Shallow", `,
+			pointer: `"Shallow", `},
+		{target: "I",
+			value:   `"Label", `,
+			pointer: `"Label", `},
+		// the standard packages are not loaded.
+		{target: "J",
+			err: "the embedded field sync.Mutex: package \"sync\" not found"},
+	}
+	for i, test := range tests {
+		name := filepath.Join(dir, fmt.Sprintf("t%v.gigo.go", i))
+		writeFiles(t, dir, map[string]string{
+			"go.mod":            "module example.com/tomate\n",
+			"store/store.go":    "package store\n\ntype Item struct {}\n\nfunc (i *Item) Label() string { return \"\" }\n",
+			filepath.Base(name): strings.Replace(src, "TARGET", test.target, 1),
+		})
+		fileDef, err := InterpretFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := Mutate(fileDef)
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%v: unexpected error wanted=%q, got=%v", test.target, test.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v: unexpected error %v", test.target, err)
+			continue
+		}
+		for _, want := range []string{
+			"Value() []string {\n  return []string{" + test.value + "}",
+			"Pointer() []string {\n  return []string{" + test.pointer + "}",
+		} {
+			if !strings.Contains(res.String(), want) {
+				t.Errorf("%v: unexpected result, %q not found in\n%v", test.target, want, res)
+			}
+		}
+	}
+}
+
+func TestMutateImplementsTarget(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gigo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tpl := `
+type Todo struct {}
+
+template <:.Name>Slice struct {}
+
+func (s *<:.Name>Slice) Push() {}
+
+template <:.Name>Wrap struct {}
+
+<:range .PointerMethodSet> func (w *<:$.Name>Wrap) <:.Name>() {}
+
+template <:.Name>Of(t Type) struct {}
+`
+	writeFiles(t, dir, map[string]string{
+		// the implements given as args resolve to the same instantiation.
+		"args.gigo.go": "package tomate\n\ntype Todos implements<:Slice .Todo> {}\n\ntype A implements<:Of .Todo .Todos> {}\n\ntype B implements<:Of .Todo .Todos> {}\n" + tpl,
+		// Todos is used before it is declared.
+		"before.gigo.go": "package tomate\n\ntype Wrapped implements<:Wrap .Todos> {}\n\ntype Todos implements<:Slice .Todo> {}\n" + tpl,
+		"cycle.gigo.go":  "package tomate\n\ntype Todos implements<:Wrap .Todos> {}\n" + tpl,
+	})
+
+	fileDef, err := InterpretFile(filepath.Join(dir, "before.gigo.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := Mutate(fileDef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for want, n := range map[string]int{
+		"func (w *TodosWrap) Push() {}": 1,
+		"type TodoSlice struct {}":      1,
+	} {
+		if got := strings.Count(res.String(), want); got != n {
+			t.Errorf("unexpected count of %q wanted=%v, got=%v in\n%v", want, n, got, res)
+		}
+	}
+
+	fileDef, err = InterpretFile(filepath.Join(dir, "args.gigo.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err = Mutate(fileDef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := 1, strings.Count(res.String(), "type TodoOf struct {}"); got != want {
+		t.Errorf("unexpected count of TodoOf wanted=%v, got=%v in\n%v", want, got, res)
+	}
+
+	name := filepath.Join(dir, "cycle.gigo.go")
+	fileDef, err = InterpretFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = Mutate(fileDef)
+	wantErr := "the implements Todos depends on its own type, at " + name + ":3:"
+	if err == nil || !strings.HasPrefix(err.Error(), wantErr) {
+		t.Errorf("unexpected error wanted=%q, got=%v", wantErr, err)
+	}
+}
+
+func TestMutateGenerics(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gigo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := `package tomate
+
+type Todo struct { Name string }
+
+type Task struct {}
+
+type Todos implements<:Slice .Todo> {}
+
+type Tasks implements<:Slice .Task> {}
+
+TPL
+`
+	tests := []struct {
+		tpl        string
+		generic    bool
+		constraint string // the constraint of T, any if empty.
+	}{
+		{tpl: "template <:.Name>Slice struct {\n  items []<:.Name>\n}\n\nfunc (s *<:.Name>Slice) Push(x <:.Name>) {}", generic: true},
+		{tpl: "template <:.Name>Slice struct {\n  items map[string]*<:.Name>\n}\n\nfunc (s *<:$.Name>Slice) At(k string) *<:$.Name> { return s.items[k] }", generic: true},
+		{tpl: "template <:.Name>Slice struct {}\n\n<:range .Props> func (s *<:$.Name>Slice) By<:.Name>() {}"},
+		{tpl: "template <:.Name>Slice struct {}\n\ntemplate(Slice) func New<:.Name>Slice() *<:.Name>Slice { return nil }"},
+		{tpl: "template <:.Name>Slice struct {\n  <:.Name>\n}"},
+		{tpl: "template <:.Name>Slice struct {\n  first<:.Name> int\n}"},
+		{tpl: "template <:.Name>Slice struct {\n  T int\n}"},
+		{tpl: "template <:.Name>Slice struct {\n  items []<:.Name>\n}\n\nfunc (s *<:.Name>Slice) Is(x <:.Name>) bool { return s.items[0] == x }", generic: true, constraint: "comparable"},
+		{tpl: "template <:.Name>Slice struct {\n  items []<:.Name>\n}\n\nfunc (s *<:.Name>Slice) First() string { return s.items[0].Name }"},
+		{tpl: "template <:.Name>Slice struct {\n  items []<:.Name>\n}\n\nfunc (s *<:.Name>Slice) Len() int { return length(s.items) }"},
+		// the package declares its own Slice.
+		{tpl: "template <:.Name>Slice struct {\n  items []<:.Name>\n}\n\ntype Slice struct {}"},
+	}
+	for i, test := range tests {
+		name := filepath.Join(dir, fmt.Sprintf("t%v.gigo.go", i))
+		writeFiles(t, dir, map[string]string{
+			filepath.Base(name): strings.Replace(src, "TPL", test.tpl, 1),
+		})
+		fileDef, err := InterpretFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		instances := NewInstances()
+		instances.Backend = Generics
+		res, err := MutateWith(fileDef, instances)
+		if err != nil {
+			t.Errorf("%v: unexpected error %v", i, err)
+			continue
+		}
+		if test.constraint == "" {
+			test.constraint = "any"
+		}
+		want := map[string]int{
+			"type Slice[T " + test.constraint + "] struct": 1,
+			"type TodoSlice = Slice[Todo]":                 1,
+			"type TaskSlice = Slice[Task]":                 1,
+			"type TodoSlice struct":                        0,
+		}
+		if !test.generic {
+			want = map[string]int{
+				"type Slice[T":          0,
+				"type TodoSlice struct": 1,
+				"type TaskSlice struct": 1,
+			}
+		}
+		for w, n := range want {
+			if got := strings.Count(res.String(), w); got != n {
+				t.Errorf("%v: unexpected count of %q wanted=%v, got=%v in\n%v", i, w, n, got, res)
+			}
+		}
+	}
+}
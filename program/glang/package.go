@@ -1,3 +1,246 @@
 package program
 
-// tbd
+import (
+	"bufio"
+	"fmt"
+	"go/build"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+
+	genericinterperter "github.com/mh-cbon/gigo/interpreter/generic"
+	"github.com/mh-cbon/gigo/interpreter/goast"
+	genericlexer "github.com/mh-cbon/gigo/lexer/generic"
+	glanglexer "github.com/mh-cbon/gigo/lexer/glang"
+	glang "github.com/mh-cbon/gigo/struct/glang"
+)
+
+// Loader loads the packages of a module from their go sources,
+// it does not need the network, nor to compile them.
+// Loaded packages and libraries are cached.
+type Loader struct {
+	From   string // directory the relative paths are resolved from.
+	Root   string // directory of the module, it contains the go.mod file.
+	Module string // path of the module declared in its go.mod file.
+	Build  build.Context
+	Fset   *token.FileSet
+
+	packages  map[string]*glang.Package
+	libraries map[string]*Library
+}
+
+// NewLoader creates a Loader for the module containing dir.
+// Without go.mod, the packages are searched in the GOPATH.
+func NewLoader(dir string) *Loader {
+	ret := &Loader{
+		Build:     build.Default,
+		Fset:      token.NewFileSet(),
+		packages:  map[string]*glang.Package{},
+		libraries: map[string]*Library{},
+	}
+	ret.Build.CgoEnabled = false
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	ret.From = dir
+	for d := dir; ; d = filepath.Dir(d) {
+		if module := readModulePath(filepath.Join(d, "go.mod")); module != "" {
+			ret.Root = d
+			ret.Module = module
+			break
+		}
+		if filepath.Dir(d) == d {
+			break
+		}
+	}
+	return ret
+}
+
+// readModulePath returns the module path declared in the go.mod file,
+// or an empty string.
+func readModulePath(file string) string {
+	f, err := os.Open(file)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if strings.HasPrefix(line, "module") {
+			return strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "module")), `"`)
+		}
+	}
+	return ""
+}
+
+// Dir returns the directory of the package at importPath,
+// ./path and ../path are relative to From.
+func (l *Loader) Dir(importPath string) (string, error) {
+	if strings.HasPrefix(importPath, "./") || strings.HasPrefix(importPath, "../") {
+		return filepath.Join(l.From, filepath.FromSlash(importPath)), nil
+	}
+	if l.Module != "" {
+		if importPath == l.Module {
+			return l.Root, nil
+		}
+		if strings.HasPrefix(importPath, l.Module+"/") {
+			return filepath.Join(l.Root, filepath.FromSlash(strings.TrimPrefix(importPath, l.Module+"/"))), nil
+		}
+	}
+	for _, p := range filepath.SplitList(l.Build.GOPATH) {
+		dir := filepath.Join(p, "src", filepath.FromSlash(importPath))
+		if s, err := os.Stat(dir); err == nil && s.IsDir() {
+			return dir, nil
+		}
+	}
+	return "", fmt.Errorf("package %q not found in the module %q, nor in the GOPATH", importPath, l.Module)
+}
+
+// Load returns the package at importPath.
+// Its go files are interpreted, the files with the gigo build tag and the tests are ignored,
+// the methods are attached to their struct.
+func (l *Loader) Load(importPath string) (*glang.Package, error) {
+	if p, ok := l.packages[importPath]; ok {
+		return p, nil
+	}
+	dir, err := l.Dir(importPath)
+	if err != nil {
+		return nil, err
+	}
+	pkg, err := l.Build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+	ret := &glang.Package{Name: pkg.Name}
+	for _, f := range pkg.GoFiles {
+		fileDef, err := goast.ParseFile(l.Fset, filepath.Join(dir, f), nil)
+		if err != nil {
+			return nil, err
+		}
+		ret.Files = append(ret.Files, fileDef)
+	}
+	structs := ret.FindStructsTypes()
+	for _, s := range structs {
+		s.Pkg = ret.Name
+	}
+	for _, f := range ret.FindFuncs() {
+		if !f.IsMethod() {
+			continue
+		}
+		name := glang.NewTypeRef(f.GetReceiverType().String()).Base().Name()
+		for _, s := range structs {
+			if s.GetName() == name {
+				s.AddMethod(f)
+			}
+		}
+	}
+	l.packages[importPath] = ret
+	return ret, nil
+}
+
+// declaredTypes returns the names of the types declared by pkg.
+func declaredTypes(pkg *glang.Package) map[string]bool {
+	ret := map[string]bool{}
+	for _, f := range pkg.Files {
+		scope, ok := f.(genericinterperter.Expressioner)
+		if !ok {
+			continue
+		}
+		for _, x := range scope.GetTokens() {
+			switch d := x.(type) {
+			case *glang.StructDecl:
+				ret[d.GetName()] = true
+			case *glang.InterfaceDecl:
+				ret[d.GetName()] = true
+			case *glang.ExpressionDecl:
+				// type ID int, the name follows the type keyword.
+				isType := false
+				for _, t := range d.GetTokens() {
+					if t.GetType() == glanglexer.TypeToken {
+						isType = true
+					} else if name, ok := t.(*glang.IdentifierDecl); ok {
+						if isType {
+							ret[name.GetValue()] = true
+						}
+						break
+					}
+				}
+			}
+		}
+	}
+	return ret
+}
+
+// qualifyTypes prefixes with pkg the types declared by the package of decl,
+// in the types of its fields and in the signatures of its methods,
+// Find(id ID) Item is written Find(id store.ID) store.Item.
+// decl is a struct or an interface loaded from the package, it is modified.
+func qualifyTypes(decl interface{}, pkg string, declared map[string]bool) {
+	var exprs []genericinterperter.Expressioner
+	var methods []glang.FuncDeclarer
+	switch x := decl.(type) {
+	case *glang.StructDecl:
+		if x.Block != nil {
+			for _, p := range x.Block.Props {
+				exprs = append(exprs, p.Type)
+			}
+			for _, u := range x.Block.Underlying {
+				exprs = append(exprs, u)
+			}
+		}
+		methods = x.Methods
+	case *glang.InterfaceDecl:
+		if x.Block != nil {
+			for _, u := range x.Block.Underlying {
+				exprs = append(exprs, u)
+			}
+		}
+		methods = x.GetMethods()
+	}
+	for _, m := range methods {
+		if args := m.GetArgs(); args != nil {
+			for _, p := range args.Props {
+				exprs = append(exprs, p.Type)
+			}
+		}
+		if out := m.GetOut(); out != nil {
+			for _, p := range out.Props {
+				exprs = append(exprs, p.Type)
+			}
+		}
+	}
+	for _, e := range exprs {
+		if e == nil {
+			continue
+		}
+		leaves := leafTokens(e)
+		for i, t := range leaves {
+			if t.GetType() != genericlexer.WordToken || !declared[t.GetValue()] {
+				continue
+			}
+			// x.Item is already qualified, Item.x is not a type.
+			if i > 0 && leaves[i-1].GetType() == glanglexer.DotToken ||
+				i+1 < len(leaves) && leaves[i+1].GetType() == glanglexer.DotToken {
+				continue
+			}
+			t.SetValue(pkg + "." + t.GetValue())
+		}
+	}
+}
+
+// leafTokens returns the tokens of e, its sub expressions are flattened.
+func leafTokens(e genericinterperter.Expressioner) []genericinterperter.Tokener {
+	ret := []genericinterperter.Tokener{}
+	for _, t := range e.GetTokens() {
+		if _, ok := t.(*genericinterperter.TokenWithPos); ok {
+			ret = append(ret, t)
+		} else if x, ok := t.(genericinterperter.Expressioner); ok {
+			ret = append(ret, leafTokens(x)...)
+		} else {
+			ret = append(ret, t)
+		}
+	}
+	return ret
+}
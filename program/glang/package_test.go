@@ -0,0 +1,158 @@
+package program
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLoader(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gigo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeFiles(t, dir, map[string]string{
+		"go.mod": "module example.com/tomate\n",
+		"models/todo.go": `package models
+
+type (
+	Todo struct {
+		Name string
+	}
+	Todos struct {
+		items []Todo
+	}
+)
+
+func (t *Todo) Hello() {}
+func (t Todo) Bye()    {}
+`,
+		"models/todo_test.go":     "package models\ntype Test struct{}\n",
+		"models/todos.gigo.go":    "// +build gigo\n\npackage models\n\ntype Gen implements<:Slice .Todo> {}\n",
+		"cmd/tomate/main.gigo.go": "package main\n",
+	})
+
+	l := NewLoader(filepath.Join(dir, "cmd", "tomate"))
+	if l.Module != "example.com/tomate" {
+		t.Errorf("unexpected module wanted=%q, got=%q", "example.com/tomate", l.Module)
+	}
+
+	pkg, err := l.Load("example.com/tomate/models")
+	if err != nil {
+		t.Errorf("%#v\n", err)
+		return
+	}
+	if pkg.Name != "models" {
+		t.Errorf("unexpected package name wanted=%q, got=%q", "models", pkg.Name)
+	}
+	structs := pkg.FindStructsTypes()
+	if len(structs) != 2 {
+		t.Errorf("unexpected structs len wanted=%v, got=%v", 2, len(structs))
+		return
+	}
+	todo := structs[0]
+	if got := todo.GetQualifiedName(); got != "models.Todo" {
+		t.Errorf("unexpected struct name wanted=%q, got=%q", "models.Todo", got)
+	}
+	if len(todo.Methods) != 2 {
+		t.Errorf("unexpected methods len wanted=%v, got=%v", 2, len(todo.Methods))
+	}
+
+	again, _ := l.Load("example.com/tomate/models")
+	if again != pkg {
+		t.Errorf("unexpected package wanted=%p, got=%p", pkg, again)
+	}
+
+	if _, err := l.Load("example.com/nop"); err == nil {
+		t.Errorf("unexpected error wanted=%v, got=%v", "an error", err)
+	}
+}
+
+func TestLoadLibrary(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gigo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeFiles(t, dir, map[string]string{
+		"go.mod": "module example.com/tomate\n",
+		"tpls/slice.gigo.go": `package tpls
+
+template <:.Name>Slice struct {
+	items []<:.Name>
+}
+
+func (s <:.Name>Slice) Len() int {
+	return len(s.items)
+}
+`,
+		"tpls/mutex.gigo": `package tpls
+
+template Mutexed<:.Name> struct {
+	lock *sync.Mutex
+	embed <:.Name>
+}
+`,
+		"tpls/other.go": "package tpls\n",
+		"bad/bad.gigo.go": `package bad
+
+func (s <:.Name>Nop) Len() int {
+	return 0
+}
+`,
+	})
+
+	l := NewLoader(filepath.Join(dir, "cmd"))
+	lib, err := l.LoadLibrary("example.com/tomate/tpls")
+	if err != nil {
+		t.Errorf("%#v\n", err)
+		return
+	}
+	if len(lib.Templates) != 2 {
+		t.Errorf("unexpected templates len wanted=%v, got=%v", 2, len(lib.Templates))
+		return
+	}
+	slice := lib.GetTemplate("Slice")
+	if slice == nil {
+		t.Errorf("unexpected template wanted=%q, got=%v", "Slice", slice)
+		return
+	}
+	if len(slice.Methods) != 1 {
+		t.Errorf("unexpected methods len wanted=%v, got=%v", 1, len(slice.Methods))
+	}
+	if lib.GetTemplate("Mutexed") == nil {
+		t.Errorf("unexpected template wanted=%q, got=%v", "Mutexed", nil)
+	}
+
+	again, err := l.LoadLibrary("../tpls")
+	if err != nil {
+		t.Errorf("%#v\n", err)
+		return
+	}
+	if len(again.Templates) != 2 {
+		t.Errorf("unexpected templates len wanted=%v, got=%v", 2, len(again.Templates))
+	}
+	if cached, _ := l.LoadLibrary("example.com/tomate/tpls"); cached != lib {
+		t.Errorf("unexpected library wanted=%p, got=%p", lib, cached)
+	}
+
+	if _, err := l.LoadLibrary("example.com/tomate/bad"); err == nil {
+		t.Errorf("unexpected error wanted=%v, got=%v", "an error", err)
+	}
+}
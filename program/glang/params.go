@@ -0,0 +1,157 @@
+package program
+
+import (
+	"fmt"
+
+	glang "github.com/mh-cbon/gigo/struct/glang"
+)
+
+// paramTypes are the types of the params a template can declare,
+// a field is the name of a field of the target struct,
+// a Type is a struct, or the name of a type.
+var paramTypes = map[string]bool{
+	"field":  true,
+	"Type":   true,
+	"string": true,
+	"bool":   true,
+	"int":    true,
+}
+
+// checkParams returns an error if a declared param of the template has an unknown type,
+// or if more than one param is variadic.
+func checkParams(m *TypeMutator) error {
+	params := m.Decl.GetParams()
+	variadic := ""
+	for _, p := range params {
+		if p.Type == nil || !paramTypes[p.Type.String()] {
+			return fmt.Errorf("unknown type of the param %v of the template %v at %v, it must be one of field, Type, string, bool, int",
+				p.Name, m.Name, m.Decl.Params.GetSpan())
+		}
+		if p.Variadic && variadic != "" {
+			return fmt.Errorf("the params %v and %v of the template %v are both variadic at %v",
+				variadic, p.Name, m.Name, m.Decl.Params.GetSpan())
+		}
+		if p.Variadic {
+			variadic = p.Name
+		}
+	}
+	return nil
+}
+
+// variadicParam returns the index of the variadic param, or -1.
+func variadicParam(params []*glang.Param) int {
+	for i, p := range params {
+		if p.Variadic {
+			return i
+		}
+	}
+	return -1
+}
+
+// paramOf returns the param that receives the arg i of n args, or nil.
+// The params that follow the variadic param receive the last args,
+// Slice(keys ...field, sorted bool).
+func paramOf(params []*glang.Param, n, i int) *glang.Param {
+	v := variadicParam(params)
+	if v < 0 || i < v {
+		if i < len(params) {
+			return params[i]
+		}
+		return nil
+	}
+	after := len(params) - v - 1
+	if i >= n-after {
+		return params[len(params)-(n-i)]
+	}
+	return params[v]
+}
+
+// bindArgs checks the args of an instantiation of m against its declared params,
+// it returns the args by param name, a variadic param receives a []interface{}.
+// The args of a template without params are not checked.
+func bindArgs(m *TypeMutator, origin *glang.StructDecl, args []interface{}) (map[string]interface{}, error) {
+	ret := map[string]interface{}{}
+	if m.Decl.Params == nil {
+		return ret, nil
+	}
+	params := m.Decl.GetParams()
+	min := len(params)
+	variadic := variadicParam(params) > -1
+	if variadic {
+		min--
+	}
+	if len(args) < min || (!variadic && len(args) > len(params)) {
+		wanted := fmt.Sprint(min)
+		if variadic {
+			wanted = "at least " + wanted
+		}
+		return nil, fmt.Errorf("wrong number of args for the template %v(%v), wanted %v, got %v",
+			m.Name, paramsString(params), wanted, len(args))
+	}
+	for _, p := range params {
+		if p.Variadic {
+			ret[p.Name] = []interface{}{}
+		}
+	}
+	for i, a := range args {
+		p := paramOf(params, len(args), i)
+		if err := checkArg(m, p, origin, a); err != nil {
+			return nil, err
+		}
+		if p.Variadic {
+			ret[p.Name] = append(ret[p.Name].([]interface{}), paramValue(p, a))
+		} else {
+			ret[p.Name] = paramValue(p, a)
+		}
+	}
+	return ret, nil
+}
+
+// paramValue returns the value of arg given to the param p,
+// the arg of a Type param is a *TypeArg.
+func paramValue(p *glang.Param, arg interface{}) interface{} {
+	if p.Type.String() == "Type" {
+		t, _ := NewTypeArg(arg)
+		return t
+	}
+	return arg
+}
+
+func checkArg(m *TypeMutator, p *glang.Param, origin *glang.StructDecl, arg interface{}) error {
+	ok := false
+	switch p.Type.String() {
+	case "field":
+		if s, isString := arg.(string); isString {
+			if origin.GetField(s) == nil {
+				return fmt.Errorf("the arg %q of the param %v of the template %v is not a field of %v",
+					s, p.Name, m.Name, origin.GetName())
+			}
+			ok = true
+		}
+	case "Type":
+		_, err := NewTypeArg(arg)
+		ok = err == nil
+	case "string":
+		_, ok = arg.(string)
+	case "bool":
+		_, ok = arg.(bool)
+	case "int":
+		_, ok = arg.(int)
+	}
+	if !ok {
+		return fmt.Errorf("the arg %#v of the param %v of the template %v is not a %v",
+			arg, p.Name, m.Name, p.Type)
+	}
+	return nil
+}
+
+func paramsString(params []*glang.Param) string {
+	ret := ""
+	for i, p := range params {
+		if i > 0 {
+			ret += ", "
+		}
+		ret += p.String()
+	}
+	return ret
+}
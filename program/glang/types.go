@@ -0,0 +1,151 @@
+package program
+
+import (
+	"fmt"
+	"go/parser"
+	"strings"
+
+	glang "github.com/mh-cbon/gigo/struct/glang"
+)
+
+// TypeArg is a type given to a template,
+// a struct like .Todo, an interface like .Store,
+// or the name of a type like "string" for a param of type Type.
+type TypeArg struct {
+	Name      string               // the name of the type, Todo, string.
+	Struct    *glang.StructDecl    // nil unless the type is a struct.
+	Interface *glang.InterfaceDecl // nil unless the type is an interface.
+}
+
+// NewTypeArg creates the TypeArg of a struct, of an interface, or of a type name.
+func NewTypeArg(t interface{}) (*TypeArg, error) {
+	switch x := t.(type) {
+	case *glang.StructDecl:
+		return &TypeArg{Name: x.GetName(), Struct: x}, nil
+	case *glang.InterfaceDecl:
+		return &TypeArg{Name: x.GetName(), Interface: x}, nil
+	case *TypeArg:
+		return x, nil
+	case string:
+		if _, err := parser.ParseExpr(x); err != nil || strings.TrimSpace(x) == "" {
+			return nil, fmt.Errorf("%q is not a type", x)
+		}
+		return &TypeArg{Name: x}, nil
+	}
+	return nil, fmt.Errorf("%#v is not a type", t)
+}
+
+// IsStruct returns true if the type is a struct.
+func (t *TypeArg) IsStruct() bool {
+	return t.Struct != nil
+}
+
+// IsInterface returns true if the type is an interface.
+func (t *TypeArg) IsInterface() bool {
+	return t.Interface != nil
+}
+
+// GetQualifiedName returns the type as written in the generated code, models.Todo, string.
+func (t *TypeArg) GetQualifiedName() string {
+	if t.Struct != nil {
+		return t.Struct.GetQualifiedName()
+	}
+	if t.Interface != nil {
+		return t.Interface.GetQualifiedName()
+	}
+	return t.Name
+}
+
+// Title returns the name with an upper case first letter,
+// to name a generated type, TodoStringMap.
+func (t *TypeArg) Title() string {
+	return upperFirst(t.Name)
+}
+
+// upperFirst returns s with an upper case first letter, if it is an ascii letter.
+func upperFirst(s string) string {
+	if s == "" || s[0] < 'a' || s[0] > 'z' {
+		return s
+	}
+	return string(s[0]-'a'+'A') + s[1:]
+}
+
+// Props returns the fields of a struct, it is empty for other types.
+func (t *TypeArg) Props() []*glang.Field {
+	if t.Struct == nil {
+		return []*glang.Field{}
+	}
+	return t.Struct.GetFields()
+}
+
+func (t *TypeArg) String() string {
+	return t.GetQualifiedName()
+}
+
+// typeArgs returns the types given to an instantiation of m,
+// the target first, then the args that are structs or interfaces, or the args of the params of type Type.
+func typeArgs(m *TypeMutator, target interface{}, args []interface{}) []*TypeArg {
+	ret := []*TypeArg{}
+	if t, err := NewTypeArg(target); err == nil {
+		ret = append(ret, t)
+	}
+	params := m.Decl.GetParams()
+	for i, a := range args {
+		if !isDecl(a) && !isTypeParam(params, len(args), i) {
+			continue
+		}
+		if t, err := NewTypeArg(a); err == nil {
+			ret = append(ret, t)
+		}
+	}
+	return ret
+}
+
+// isDecl returns true if x is a struct or an interface.
+func isDecl(x interface{}) bool {
+	switch x.(type) {
+	case *glang.StructDecl, *glang.InterfaceDecl:
+		return true
+	}
+	return false
+}
+
+// isTypeParam returns true if the arg i of n args is given to a param of type Type.
+func isTypeParam(params []*glang.Param, n, i int) bool {
+	p := paramOf(params, n, i)
+	return p != nil && p.Type != nil && p.Type.String() == "Type"
+}
+
+// builtinStruct returns a struct without fields named after a type name,
+// so a template can target a type that is not a struct, Map "string" .Todo.
+func builtinStruct(name string) (*glang.StructDecl, error) {
+	if _, err := NewTypeArg(name); err != nil {
+		return nil, err
+	}
+	if strings.ContainsAny(name, ".[]*( ") {
+		return nil, fmt.Errorf("%q is not a type name", name)
+	}
+	// string is a keyword of the lexer, the struct is renamed once read.
+	fileDef, err := InterpretString("builtin", "package builtin\n\ntype T struct{}\n")
+	if err != nil {
+		return nil, err
+	}
+	ret := fileDef.FindStructsTypes()[0]
+	ret.Name.SetValue(name)
+	return ret, nil
+}
+
+// interfaceStruct returns a struct without fields named after an interface,
+// its methods are the signatures of the interface,
+// so a template ranges over .Methods of an interface as it does for a struct.
+func interfaceStruct(i *glang.InterfaceDecl) (*glang.StructDecl, error) {
+	ret, err := builtinStruct(i.GetName())
+	if err != nil {
+		return nil, err
+	}
+	ret.Pkg = i.Pkg
+	for _, m := range i.GetMethods() {
+		ret.AddMethod(m)
+	}
+	return ret, nil
+}
@@ -0,0 +1,106 @@
+package glang
+
+import (
+	"fmt"
+	"strings"
+
+	genericinterperter "github.com/mh-cbon/gigo/interpreter/generic"
+)
+
+// ImplementChain is a template expression of an implements declaration,
+// implements<:.Todo | Slice "Name" | Mutexed>
+// applies Slice to .Todo with the argument "Name", then Mutexed to its result.
+// An expression without pipeline, implements<:Mutexed .Todo>, is a chain without stages.
+type ImplementChain struct {
+	genericinterperter.Expression
+	Source *ExpressionDecl // the type the pipeline starts from, .Todo, models.Todo.
+	Stages []*ChainStage
+}
+
+// ChainStage is an element of an ImplementChain, Slice "Name".
+type ChainStage struct {
+	genericinterperter.Expression
+	Template *IdentifierDecl   // the template, tpl.Name for a library template.
+	Args     []*ExpressionDecl // the template expressions of its arguments.
+}
+
+// GetName returns the name of the template of the stage.
+func (s *ChainStage) GetName() string {
+	return s.Template.String()
+}
+
+// GetArgs returns the template expressions of the arguments of the stage.
+func (s *ChainStage) GetArgs() []string {
+	ret := []string{}
+	for _, a := range s.Args {
+		ret = append(ret, a.String())
+	}
+	return ret
+}
+
+func (s *ChainStage) String() string {
+	return s.Expression.String()
+}
+
+// NewChainStage creates a new ChainStage
+func NewChainStage() *ChainStage {
+	return &ChainStage{}
+}
+
+// Expr returns the equivalent template expression,
+// Mutexed (Slice .Todo "Name").
+func (c *ImplementChain) Expr() string {
+	ret := c.Source.String()
+	for _, s := range c.Stages {
+		if strings.ContainsAny(ret, " \t") {
+			ret = "(" + ret + ")"
+		}
+		ret = strings.Join(append([]string{s.GetName(), ret}, s.GetArgs()...), " ")
+	}
+	return ret
+}
+
+// Validate returns an error for the first stage
+// whose template is unknown, or whose args do not match the declared params of its template.
+// lookup returns the template of a name, it is nil for a builtin,
+// the args of a template without params are not checked.
+func (c *ImplementChain) Validate(lookup func(name string) (*TemplateDecl, bool)) error {
+	for _, s := range c.Stages {
+		t, ok := lookup(s.GetName())
+		if !ok {
+			return fmt.Errorf("unknown template %q in the implements pipeline at %v", s.GetName(), s.GetSpan())
+		}
+		if t == nil || t.Params == nil {
+			continue
+		}
+		params := t.GetParams()
+		min := len(params)
+		variadic := false
+		decl := []string{}
+		for _, p := range params {
+			if p.Variadic {
+				variadic = true
+				min--
+			}
+			decl = append(decl, p.String())
+		}
+		if n := len(s.Args); n < min || (!variadic && n > len(params)) {
+			wanted := fmt.Sprint(min)
+			if variadic {
+				wanted = "at least " + wanted
+			}
+			return fmt.Errorf("wrong number of args for the template %v(%v), wanted %v, got %v in the implements pipeline at %v",
+				s.GetName(), strings.Join(decl, ", "), wanted, n, s.GetSpan())
+		}
+	}
+	return nil
+}
+
+func (c *ImplementChain) String() string {
+	return c.Expression.String()
+}
+
+// NewImplementChain creates a new ImplementChain
+func NewImplementChain() *ImplementChain {
+	return &ImplementChain{}
+}
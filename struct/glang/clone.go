@@ -0,0 +1,730 @@
+package glang
+
+import (
+	genericinterperter "github.com/mh-cbon/gigo/interpreter/generic"
+)
+
+// Clone methods return a deep copy of a declaration,
+// the copy can be mutated without changing the original tree.
+// CloneWith methods implement genericinterperter.Cloneable,
+// they copy a node within a Cloner so nodes shared by fields and tokens stay shared.
+
+// Clone returns a deep copy of the ScopeDecl.
+func (p *ScopeDecl) Clone() *ScopeDecl {
+	return genericinterperter.NewCloner().Clone(p).(*ScopeDecl)
+}
+
+func (p *ScopeDecl) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &ScopeDecl{}
+	c.Register(p, ret)
+	p.cloneInto(c, ret)
+	return ret
+}
+
+func (p *ScopeDecl) cloneInto(c *genericinterperter.Cloner, ret *ScopeDecl) {
+	ret.Expression = c.Expression(p.Expression)
+}
+
+// Clone returns a deep copy of the StrDecl.
+func (p *StrDecl) Clone() *StrDecl {
+	return genericinterperter.NewCloner().Clone(p).(*StrDecl)
+}
+
+func (p *StrDecl) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &StrDecl{}
+	c.Register(p, ret)
+	p.ScopeDecl.cloneInto(c, &ret.ScopeDecl)
+	ret.Src = p.Src
+	return ret
+}
+
+// Clone returns a deep copy of the FileDecl.
+func (p *FileDecl) Clone() *FileDecl {
+	return genericinterperter.NewCloner().Clone(p).(*FileDecl)
+}
+
+func (p *FileDecl) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &FileDecl{}
+	c.Register(p, ret)
+	p.ScopeDecl.cloneInto(c, &ret.ScopeDecl)
+	ret.Name = p.Name
+	return ret
+}
+
+// Clone returns a deep copy of the PackageDecl.
+func (p *PackageDecl) Clone() *PackageDecl {
+	return genericinterperter.NewCloner().Clone(p).(*PackageDecl)
+}
+
+func (p *PackageDecl) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &PackageDecl{}
+	c.Register(p, ret)
+	ret.Expression = c.Expression(p.Expression)
+	ret.Name = c.Clone(p.Name)
+	return ret
+}
+
+// Clone returns a deep copy of the BodyBlockDecl.
+func (p *BodyBlockDecl) Clone() *BodyBlockDecl {
+	return genericinterperter.NewCloner().Clone(p).(*BodyBlockDecl)
+}
+
+func (p *BodyBlockDecl) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &BodyBlockDecl{}
+	c.Register(p, ret)
+	p.cloneInto(c, ret)
+	return ret
+}
+
+func (p *BodyBlockDecl) cloneInto(c *genericinterperter.Cloner, ret *BodyBlockDecl) {
+	ret.Expression = c.Expression(p.Expression)
+	ret.Open = c.Clone(p.Open)
+	ret.Close = c.Clone(p.Close)
+}
+
+// Clone returns a deep copy of the StructDecl.
+func (p *StructDecl) Clone() *StructDecl {
+	return genericinterperter.NewCloner().Clone(p).(*StructDecl)
+}
+
+func (p *StructDecl) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &StructDecl{}
+	c.Register(p, ret)
+	ret.Expression = c.Expression(p.Expression)
+	ret.Name, _ = c.Clone(p.Name).(*IdentifierDecl)
+	for _, x := range p.Methods {
+		ret.Methods = append(ret.Methods, c.Clone(x.(genericinterperter.Tokener)).(FuncDeclarer))
+	}
+	ret.Block, _ = c.Clone(p.Block).(*PropsBlockDecl)
+	ret.TypeParams, _ = c.Clone(p.TypeParams).(*PropsBlockDecl)
+	ret.Pkg = p.Pkg
+	return ret
+}
+
+// Clone returns a deep copy of the TemplateDecl.
+func (p *TemplateDecl) Clone() *TemplateDecl {
+	return genericinterperter.NewCloner().Clone(p).(*TemplateDecl)
+}
+
+func (p *TemplateDecl) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &TemplateDecl{}
+	c.Register(p, ret)
+	ret.Expression = c.Expression(p.Expression)
+	ret.Name, _ = c.Clone(p.Name).(*IdentifierDecl)
+	ret.Params, _ = c.Clone(p.Params).(*PropsBlockDecl)
+	for _, x := range p.Methods {
+		ret.Methods = append(ret.Methods, c.Clone(x.(genericinterperter.Tokener)).(FuncDeclarer))
+	}
+	for _, x := range p.Extras {
+		ret.Extras = append(ret.Extras, c.Clone(x).(*TemplateExtraDecl))
+	}
+	ret.Block, _ = c.Clone(p.Block).(*PropsBlockDecl)
+	return ret
+}
+
+// Clone returns a deep copy of the TemplateExtraDecl.
+func (p *TemplateExtraDecl) Clone() *TemplateExtraDecl {
+	return genericinterperter.NewCloner().Clone(p).(*TemplateExtraDecl)
+}
+
+func (p *TemplateExtraDecl) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &TemplateExtraDecl{}
+	c.Register(p, ret)
+	ret.Expression = c.Expression(p.Expression)
+	ret.Of, _ = c.Clone(p.Of).(*IdentifierDecl)
+	ret.Decl = c.Clone(p.Decl)
+	return ret
+}
+
+// Clone returns a deep copy of the InterfaceDecl.
+func (p *InterfaceDecl) Clone() *InterfaceDecl {
+	return genericinterperter.NewCloner().Clone(p).(*InterfaceDecl)
+}
+
+func (p *InterfaceDecl) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &InterfaceDecl{}
+	c.Register(p, ret)
+	ret.Expression = c.Expression(p.Expression)
+	ret.Name, _ = c.Clone(p.Name).(*IdentifierDecl)
+	ret.Block, _ = c.Clone(p.Block).(*SignsBlockDecl)
+	ret.Pkg = p.Pkg
+	return ret
+}
+
+// Clone returns a deep copy of the ImplementDecl.
+func (p *ImplementDecl) Clone() *ImplementDecl {
+	return genericinterperter.NewCloner().Clone(p).(*ImplementDecl)
+}
+
+func (p *ImplementDecl) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &ImplementDecl{}
+	c.Register(p, ret)
+	ret.Expression = c.Expression(p.Expression)
+	ret.Name, _ = c.Clone(p.Name).(*IdentifierDecl)
+	ret.ImplementTemplate = c.Clone(p.ImplementTemplate)
+	for _, x := range p.Chains {
+		ret.Chains = append(ret.Chains, c.Clone(x).(*ImplementChain))
+	}
+	for _, x := range p.Methods {
+		ret.Methods = append(ret.Methods, c.Clone(x.(genericinterperter.Tokener)).(FuncDeclarer))
+	}
+	return ret
+}
+
+// Clone returns a deep copy of the ImplementChain.
+func (p *ImplementChain) Clone() *ImplementChain {
+	return genericinterperter.NewCloner().Clone(p).(*ImplementChain)
+}
+
+func (p *ImplementChain) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &ImplementChain{}
+	c.Register(p, ret)
+	ret.Expression = c.Expression(p.Expression)
+	ret.Source, _ = c.Clone(p.Source).(*ExpressionDecl)
+	for _, x := range p.Stages {
+		ret.Stages = append(ret.Stages, c.Clone(x).(*ChainStage))
+	}
+	return ret
+}
+
+// Clone returns a deep copy of the ChainStage.
+func (p *ChainStage) Clone() *ChainStage {
+	return genericinterperter.NewCloner().Clone(p).(*ChainStage)
+}
+
+func (p *ChainStage) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &ChainStage{}
+	c.Register(p, ret)
+	ret.Expression = c.Expression(p.Expression)
+	ret.Template, _ = c.Clone(p.Template).(*IdentifierDecl)
+	for _, x := range p.Args {
+		ret.Args = append(ret.Args, c.Clone(x).(*ExpressionDecl))
+	}
+	return ret
+}
+
+// Clone returns a deep copy of the PoireauDecl.
+func (p *PoireauDecl) Clone() *PoireauDecl {
+	return genericinterperter.NewCloner().Clone(p).(*PoireauDecl)
+}
+
+func (p *PoireauDecl) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &PoireauDecl{}
+	c.Register(p, ret)
+	ret.Expression = c.Expression(p.Expression)
+	ret.ImplementTemplate, _ = c.Clone(p.ImplementTemplate).(*IdentifierDecl)
+	return ret
+}
+
+// Clone returns a deep copy of the TemplateFuncDecl.
+func (p *TemplateFuncDecl) Clone() *TemplateFuncDecl {
+	return genericinterperter.NewCloner().Clone(p).(*TemplateFuncDecl)
+}
+
+func (p *TemplateFuncDecl) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &TemplateFuncDecl{}
+	c.Register(p, ret)
+	ret.Expression = c.Expression(p.Expression)
+	ret.Func, _ = c.Clone(p.Func).(*FuncDecl)
+	ret.Modifier, _ = c.Clone(p.Modifier).(*BodyBlockDecl)
+	return ret
+}
+
+// Clone returns a deep copy of the FuncDecl.
+func (p *FuncDecl) Clone() *FuncDecl {
+	return genericinterperter.NewCloner().Clone(p).(*FuncDecl)
+}
+
+func (p *FuncDecl) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &FuncDecl{}
+	c.Register(p, ret)
+	ret.Expression = c.Expression(p.Expression)
+	ret.Receiver, _ = c.Clone(p.Receiver).(*PropsBlockDecl)
+	ret.Name, _ = c.Clone(p.Name).(*IdentifierDecl)
+	ret.Params, _ = c.Clone(p.Params).(*PropsBlockDecl)
+	ret.Out, _ = c.Clone(p.Out).(*PropsBlockDecl)
+	ret.Body, _ = c.Clone(p.Body).(*BodyBlockDecl)
+	return ret
+}
+
+// Clone returns a deep copy of the SignsBlockDecl.
+func (p *SignsBlockDecl) Clone() *SignsBlockDecl {
+	return genericinterperter.NewCloner().Clone(p).(*SignsBlockDecl)
+}
+
+func (p *SignsBlockDecl) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &SignsBlockDecl{}
+	c.Register(p, ret)
+	ret.Expression = c.Expression(p.Expression)
+	for _, x := range p.Underlying {
+		ret.Underlying = append(ret.Underlying, c.Clone(x).(*IdentifierDecl))
+	}
+	for _, x := range p.Signs {
+		ret.Signs = append(ret.Signs, c.Clone(x).(*FuncDecl))
+	}
+	return ret
+}
+
+// Clone returns a deep copy of the PropsBlockDecl.
+func (p *PropsBlockDecl) Clone() *PropsBlockDecl {
+	return genericinterperter.NewCloner().Clone(p).(*PropsBlockDecl)
+}
+
+func (p *PropsBlockDecl) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &PropsBlockDecl{}
+	c.Register(p, ret)
+	ret.Expression = c.Expression(p.Expression)
+	for _, x := range p.Poireaux {
+		ret.Poireaux = append(ret.Poireaux, c.Clone(x).(*PoireauDecl))
+	}
+	for _, x := range p.Underlying {
+		ret.Underlying = append(ret.Underlying, c.Clone(x).(*ExpressionDecl))
+	}
+	for _, x := range p.Props {
+		ret.Props = append(ret.Props, c.Clone(x).(*PropDecl))
+	}
+	for k, v := range p.underlyingTags {
+		ret.SetUnderlyingTag(c.Clone(k).(*ExpressionDecl), c.Clone(v).(*TagDecl))
+	}
+	return ret
+}
+
+// Clone returns a deep copy of the AssignsBlockDecl.
+func (p *AssignsBlockDecl) Clone() *AssignsBlockDecl {
+	return genericinterperter.NewCloner().Clone(p).(*AssignsBlockDecl)
+}
+
+func (p *AssignsBlockDecl) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &AssignsBlockDecl{}
+	c.Register(p, ret)
+	ret.Expression = c.Expression(p.Expression)
+	for _, x := range p.Assigns {
+		ret.Assigns = append(ret.Assigns, c.Clone(x).(*AssignDecl))
+	}
+	return ret
+}
+
+// Clone returns a deep copy of the ReturnDecl.
+func (p *ReturnDecl) Clone() *ReturnDecl {
+	return genericinterperter.NewCloner().Clone(p).(*ReturnDecl)
+}
+
+func (p *ReturnDecl) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &ReturnDecl{}
+	c.Register(p, ret)
+	ret.Expression = c.Expression(p.Expression)
+	return ret
+}
+
+// Clone returns a deep copy of the AssignDecl.
+func (p *AssignDecl) Clone() *AssignDecl {
+	return genericinterperter.NewCloner().Clone(p).(*AssignDecl)
+}
+
+func (p *AssignDecl) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &AssignDecl{}
+	c.Register(p, ret)
+	ret.Expression = c.Expression(p.Expression)
+	ret.Left = c.Clone(p.Left)
+	ret.LeftType = c.Clone(p.LeftType)
+	ret.Assign = c.Clone(p.Assign)
+	ret.Right = c.Clone(p.Right)
+	return ret
+}
+
+// Clone returns a deep copy of the PropDecl.
+func (p *PropDecl) Clone() *PropDecl {
+	return genericinterperter.NewCloner().Clone(p).(*PropDecl)
+}
+
+func (p *PropDecl) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &PropDecl{}
+	c.Register(p, ret)
+	ret.Expression = c.Expression(p.Expression)
+	ret.Name, _ = c.Clone(p.Name).(*IdentifierDecl)
+	ret.Type, _ = c.Clone(p.Type).(*ExpressionDecl)
+	ret.Tag, _ = c.Clone(p.Tag).(*TagDecl)
+	return ret
+}
+
+// Clone returns a deep copy of the TagDecl.
+func (p *TagDecl) Clone() *TagDecl {
+	return genericinterperter.NewCloner().Clone(p).(*TagDecl)
+}
+
+func (p *TagDecl) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &TagDecl{}
+	c.Register(p, ret)
+	ret.Expression = c.Expression(p.Expression)
+	return ret
+}
+
+// Clone returns a deep copy of the IdentifierDecl.
+func (p *IdentifierDecl) Clone() *IdentifierDecl {
+	return genericinterperter.NewCloner().Clone(p).(*IdentifierDecl)
+}
+
+func (p *IdentifierDecl) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &IdentifierDecl{}
+	c.Register(p, ret)
+	ret.Expression = c.Expression(p.Expression)
+	return ret
+}
+
+// Clone returns a deep copy of the VarDecl.
+func (p *VarDecl) Clone() *VarDecl {
+	return genericinterperter.NewCloner().Clone(p).(*VarDecl)
+}
+
+func (p *VarDecl) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &VarDecl{}
+	c.Register(p, ret)
+	ret.Expression = c.Expression(p.Expression)
+	for _, x := range p.Assignments {
+		ret.Assignments = append(ret.Assignments, c.Clone(x.(genericinterperter.Tokener)).(AssignDeclarer))
+	}
+	return ret
+}
+
+// Clone returns a deep copy of the ConstDecl.
+func (p *ConstDecl) Clone() *ConstDecl {
+	return genericinterperter.NewCloner().Clone(p).(*ConstDecl)
+}
+
+func (p *ConstDecl) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &ConstDecl{}
+	c.Register(p, ret)
+	ret.Expression = c.Expression(p.Expression)
+	for _, x := range p.Assignments {
+		ret.Assignments = append(ret.Assignments, c.Clone(x.(genericinterperter.Tokener)).(AssignDeclarer))
+	}
+	return ret
+}
+
+// Clone returns a deep copy of the ExpressionDecl.
+func (p *ExpressionDecl) Clone() *ExpressionDecl {
+	return genericinterperter.NewCloner().Clone(p).(*ExpressionDecl)
+}
+
+func (p *ExpressionDecl) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &ExpressionDecl{}
+	c.Register(p, ret)
+	ret.Expression = c.Expression(p.Expression)
+	return ret
+}
+
+// Clone returns a deep copy of the CallExprBlock.
+func (p *CallExprBlock) Clone() *CallExprBlock {
+	return genericinterperter.NewCloner().Clone(p).(*CallExprBlock)
+}
+
+func (p *CallExprBlock) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &CallExprBlock{}
+	c.Register(p, ret)
+	ret.Expression = c.Expression(p.Expression)
+	ret.Open = c.Clone(p.Open)
+	ret.Close = c.Clone(p.Close)
+	for _, x := range p.Params {
+		ret.Params = append(ret.Params, c.Clone(x).(*ExpressionDecl))
+	}
+	return ret
+}
+
+// Clone returns a deep copy of the CallExpr.
+func (p *CallExpr) Clone() *CallExpr {
+	return genericinterperter.NewCloner().Clone(p).(*CallExpr)
+}
+
+func (p *CallExpr) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &CallExpr{}
+	c.Register(p, ret)
+	ret.Expression = c.Expression(p.Expression)
+	ret.ID, _ = c.Clone(p.ID).(*IdentifierDecl)
+	ret.Params, _ = c.Clone(p.Params).(*CallExprBlock)
+	return ret
+}
+
+// Clone returns a deep copy of the AssignExpr.
+func (p *AssignExpr) Clone() *AssignExpr {
+	return genericinterperter.NewCloner().Clone(p).(*AssignExpr)
+}
+
+func (p *AssignExpr) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &AssignExpr{}
+	c.Register(p, ret)
+	ret.Expression = c.Expression(p.Expression)
+	for _, x := range p.IDs {
+		ret.IDs = append(ret.IDs, c.Clone(x).(*IdentifierDecl))
+	}
+	for _, x := range p.Values {
+		ret.Values = append(ret.Values, c.Clone(x).(*ExpressionDecl))
+	}
+	return ret
+}
+
+// Clone returns a deep copy of the ForStmt.
+func (p *ForStmt) Clone() *ForStmt {
+	return genericinterperter.NewCloner().Clone(p).(*ForStmt)
+}
+
+func (p *ForStmt) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &ForStmt{}
+	c.Register(p, ret)
+	ret.Expression = c.Expression(p.Expression)
+	ret.Init, _ = c.Clone(p.Init).(*AssignExpr)
+	ret.Cond, _ = c.Clone(p.Cond).(*ExpressionDecl)
+	ret.Post, _ = c.Clone(p.Post).(*ExpressionDecl)
+	ret.Body, _ = c.Clone(p.Body).(*BodyBlockDecl)
+	return ret
+}
+
+// Clone returns a deep copy of the IfStmt.
+func (p *IfStmt) Clone() *IfStmt {
+	return genericinterperter.NewCloner().Clone(p).(*IfStmt)
+}
+
+func (p *IfStmt) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &IfStmt{}
+	c.Register(p, ret)
+	p.cloneInto(c, ret)
+	return ret
+}
+
+func (p *IfStmt) cloneInto(c *genericinterperter.Cloner, ret *IfStmt) {
+	ret.Expression = c.Expression(p.Expression)
+	ret.Init, _ = c.Clone(p.Init).(*AssignExpr)
+	ret.Cond = c.Clone(p.Cond)
+	ret.Body, _ = c.Clone(p.Body).(*BodyBlockDecl)
+	ret.Else, _ = c.Clone(p.Else).(*ElseStmt)
+}
+
+// Clone returns a deep copy of the ElseStmt.
+func (p *ElseStmt) Clone() *ElseStmt {
+	return genericinterperter.NewCloner().Clone(p).(*ElseStmt)
+}
+
+func (p *ElseStmt) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &ElseStmt{}
+	c.Register(p, ret)
+	p.IfStmt.cloneInto(c, &ret.IfStmt)
+	return ret
+}
+
+// Clone returns a deep copy of the SwitchStmt.
+func (p *SwitchStmt) Clone() *SwitchStmt {
+	return genericinterperter.NewCloner().Clone(p).(*SwitchStmt)
+}
+
+func (p *SwitchStmt) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &SwitchStmt{}
+	c.Register(p, ret)
+	ret.Expression = c.Expression(p.Expression)
+	ret.Init, _ = c.Clone(p.Init).(*AssignExpr)
+	ret.Cond = c.Clone(p.Cond)
+	ret.Body, _ = c.Clone(p.Body).(*BranchedStmtBlock)
+	return ret
+}
+
+// Clone returns a deep copy of the TypeSwitchStmt.
+func (p *TypeSwitchStmt) Clone() *TypeSwitchStmt {
+	return genericinterperter.NewCloner().Clone(p).(*TypeSwitchStmt)
+}
+
+func (p *TypeSwitchStmt) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &TypeSwitchStmt{}
+	c.Register(p, ret)
+	ret.Expression = c.Expression(p.Expression)
+	ret.Init, _ = c.Clone(p.Init).(*AssignExpr)
+	ret.Assert, _ = c.Clone(p.Assert).(*TypeAssertExpr)
+	ret.Body, _ = c.Clone(p.Body).(*BranchedStmtBlock)
+	return ret
+}
+
+// Clone returns a deep copy of the TypeAssertExpr.
+func (p *TypeAssertExpr) Clone() *TypeAssertExpr {
+	return genericinterperter.NewCloner().Clone(p).(*TypeAssertExpr)
+}
+
+func (p *TypeAssertExpr) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &TypeAssertExpr{}
+	c.Register(p, ret)
+	ret.Expression = c.Expression(p.Expression)
+	ret.X = c.Clone(p.X)
+	ret.Type, _ = c.Clone(p.Type).(*ExpressionDecl)
+	return ret
+}
+
+// Clone returns a deep copy of the LabeledStmt.
+func (p *LabeledStmt) Clone() *LabeledStmt {
+	return genericinterperter.NewCloner().Clone(p).(*LabeledStmt)
+}
+
+func (p *LabeledStmt) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &LabeledStmt{}
+	c.Register(p, ret)
+	ret.Expression = c.Expression(p.Expression)
+	ret.Label, _ = c.Clone(p.Label).(*IdentifierDecl)
+	ret.Stmt = c.Clone(p.Stmt)
+	return ret
+}
+
+// Clone returns a deep copy of the JumpStmt.
+func (p *JumpStmt) Clone() *JumpStmt {
+	return genericinterperter.NewCloner().Clone(p).(*JumpStmt)
+}
+
+func (p *JumpStmt) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &JumpStmt{}
+	c.Register(p, ret)
+	ret.Expression = c.Expression(p.Expression)
+	ret.Keyword = c.Clone(p.Keyword)
+	ret.Label, _ = c.Clone(p.Label).(*IdentifierDecl)
+	return ret
+}
+
+// Clone returns a deep copy of the FuncLit.
+func (p *FuncLit) Clone() *FuncLit {
+	return genericinterperter.NewCloner().Clone(p).(*FuncLit)
+}
+
+func (p *FuncLit) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &FuncLit{}
+	c.Register(p, ret)
+	ret.Expression = c.Expression(p.Expression)
+	ret.Func, _ = c.Clone(p.Func).(*FuncDecl)
+	return ret
+}
+
+// Clone returns a deep copy of the CompositeLit.
+func (p *CompositeLit) Clone() *CompositeLit {
+	return genericinterperter.NewCloner().Clone(p).(*CompositeLit)
+}
+
+func (p *CompositeLit) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &CompositeLit{}
+	c.Register(p, ret)
+	ret.Expression = c.Expression(p.Expression)
+	ret.Type = c.Clone(p.Type)
+	ret.Open = c.Clone(p.Open)
+	ret.Close = c.Clone(p.Close)
+	ret.Elts = c.Tokens(p.Elts)
+	return ret
+}
+
+// Clone returns a deep copy of the KeyValueExpr.
+func (p *KeyValueExpr) Clone() *KeyValueExpr {
+	return genericinterperter.NewCloner().Clone(p).(*KeyValueExpr)
+}
+
+func (p *KeyValueExpr) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &KeyValueExpr{}
+	c.Register(p, ret)
+	ret.Expression = c.Expression(p.Expression)
+	ret.Key = c.Clone(p.Key)
+	ret.Value = c.Clone(p.Value)
+	return ret
+}
+
+// Clone returns a deep copy of the IndexExpr.
+func (p *IndexExpr) Clone() *IndexExpr {
+	return genericinterperter.NewCloner().Clone(p).(*IndexExpr)
+}
+
+func (p *IndexExpr) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &IndexExpr{}
+	c.Register(p, ret)
+	ret.Expression = c.Expression(p.Expression)
+	ret.X = c.Clone(p.X)
+	ret.Index, _ = c.Clone(p.Index).(*ExpressionDecl)
+	return ret
+}
+
+// Clone returns a deep copy of the SliceExpr.
+func (p *SliceExpr) Clone() *SliceExpr {
+	return genericinterperter.NewCloner().Clone(p).(*SliceExpr)
+}
+
+func (p *SliceExpr) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &SliceExpr{}
+	c.Register(p, ret)
+	ret.Expression = c.Expression(p.Expression)
+	ret.X = c.Clone(p.X)
+	ret.Low, _ = c.Clone(p.Low).(*ExpressionDecl)
+	ret.High, _ = c.Clone(p.High).(*ExpressionDecl)
+	ret.Max, _ = c.Clone(p.Max).(*ExpressionDecl)
+	ret.Slice3 = p.Slice3
+	return ret
+}
+
+// Clone returns a deep copy of the UnaryExpr.
+func (p *UnaryExpr) Clone() *UnaryExpr {
+	return genericinterperter.NewCloner().Clone(p).(*UnaryExpr)
+}
+
+func (p *UnaryExpr) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &UnaryExpr{}
+	c.Register(p, ret)
+	ret.Expression = c.Expression(p.Expression)
+	ret.Op = c.Clone(p.Op)
+	ret.X = c.Clone(p.X)
+	return ret
+}
+
+// Clone returns a deep copy of the BranchedStmtBlock.
+func (p *BranchedStmtBlock) Clone() *BranchedStmtBlock {
+	return genericinterperter.NewCloner().Clone(p).(*BranchedStmtBlock)
+}
+
+func (p *BranchedStmtBlock) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &BranchedStmtBlock{}
+	c.Register(p, ret)
+	p.BodyBlockDecl.cloneInto(c, &ret.BodyBlockDecl)
+	for _, x := range p.Branches {
+		ret.Branches = append(ret.Branches, c.Clone(x).(*BranchStmt))
+	}
+	return ret
+}
+
+// Clone returns a deep copy of the BranchStmt.
+func (p *BranchStmt) Clone() *BranchStmt {
+	return genericinterperter.NewCloner().Clone(p).(*BranchStmt)
+}
+
+func (p *BranchStmt) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &BranchStmt{}
+	c.Register(p, ret)
+	ret.Expression = c.Expression(p.Expression)
+	ret.Cond = c.Clone(p.Cond)
+	ret.Body, _ = c.Clone(p.Body).(*BodyBlockDecl)
+	return ret
+}
+
+// Clone returns a deep copy of the WithStmt.
+func (p *WithStmt) Clone() *WithStmt {
+	return genericinterperter.NewCloner().Clone(p).(*WithStmt)
+}
+
+func (p *WithStmt) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &WithStmt{}
+	c.Register(p, ret)
+	ret.Expression = c.Expression(p.Expression)
+	ret.Init, _ = c.Clone(p.Init).(*AssignExpr)
+	ret.Cond = c.Clone(p.Cond)
+	ret.Body, _ = c.Clone(p.Body).(*BodyBlockDecl)
+	return ret
+}
+
+// Clone returns a deep copy of the BinaryExpr.
+func (p *BinaryExpr) Clone() *BinaryExpr {
+	return genericinterperter.NewCloner().Clone(p).(*BinaryExpr)
+}
+
+func (p *BinaryExpr) CloneWith(c *genericinterperter.Cloner) genericinterperter.Tokener {
+	ret := &BinaryExpr{}
+	c.Register(p, ret)
+	ret.Expression = c.Expression(p.Expression)
+	ret.Left, _ = c.Clone(p.Left).(*ExpressionDecl)
+	ret.Op = c.Clone(p.Op)
+	ret.Right, _ = c.Clone(p.Right).(*ExpressionDecl)
+	return ret
+}
@@ -2,9 +2,11 @@ package glang
 
 import (
 	"regexp"
+	"strconv"
 	"strings"
 
 	genericinterperter "github.com/mh-cbon/gigo/interpreter/generic"
+	genericlexer "github.com/mh-cbon/gigo/lexer/generic"
 	glanglexer "github.com/mh-cbon/gigo/lexer/glang"
 )
 
@@ -62,6 +64,17 @@ func (f *ScopeDecl) FindTemplatesTypes() []*TemplateDecl {
 	return ret
 }
 
+// FindTemplateExtras returns all template(X) declarations found.
+func (f *ScopeDecl) FindTemplateExtras() []*TemplateExtraDecl {
+	var ret []*TemplateExtraDecl
+	for _, t := range f.Tokens {
+		if x, ok := t.(*TemplateExtraDecl); ok {
+			ret = append(ret, x)
+		}
+	}
+	return ret
+}
+
 // FindInterfaces returns all interface type declarations.
 func (f *ScopeDecl) FindInterfaces() []*InterfaceDecl {
 	var ret []*InterfaceDecl
@@ -133,6 +146,98 @@ func (f *ScopeDecl) FindConstDecl() []*ConstDecl {
 	return ret
 }
 
+// ImportSpec is an imported package.
+type ImportSpec struct {
+	Name string // the name given to the import, or empty.
+	Path string
+}
+
+// FindImports returns all imported packages.
+func (f *ScopeDecl) FindImports() []ImportSpec {
+	var ret []ImportSpec
+	in := false
+	depth := 0
+	name := ""
+	for _, t := range f.Tokens {
+		switch t.GetType() {
+		case glanglexer.ImportToken:
+			in = true
+		case glanglexer.ParenOpenToken:
+			if in {
+				depth++
+			}
+		case glanglexer.ParenCloseToken:
+			if in {
+				depth--
+				in = depth > 0
+			}
+		case genericlexer.WordToken, glanglexer.DotToken:
+			if in {
+				name = t.GetValue()
+			}
+		case genericlexer.TextToken:
+			if in {
+				p, err := strconv.Unquote(t.GetValue())
+				if err == nil {
+					ret = append(ret, ImportSpec{Name: name, Path: p})
+				}
+				name = ""
+				in = depth > 0
+			}
+		}
+	}
+	return ret
+}
+
+// FindUses returns the template libraries used with a directive such as
+// //gigo:use tpl "path/to/templates", the name is optional.
+func (f *ScopeDecl) FindUses() []ImportSpec {
+	var ret []ImportSpec
+	for _, t := range leadingComments(f.Tokens) {
+		v := strings.TrimSpace(strings.TrimPrefix(t.GetValue(), "//"))
+		if !strings.HasPrefix(v, "gigo:use ") {
+			continue
+		}
+		v = strings.TrimSpace(strings.TrimPrefix(v, "gigo:use "))
+		spec := ImportSpec{}
+		if i := strings.IndexAny(v, "\"`"); i > 0 {
+			spec.Name = strings.TrimSpace(v[:i])
+			v = v[i:]
+		}
+		p, err := strconv.Unquote(v)
+		if err != nil {
+			continue
+		}
+		spec.Path = p
+		ret = append(ret, spec)
+	}
+	return ret
+}
+
+// leadingComments returns the line comments of tokens,
+// and the line comments kept at the beginning of their declarations.
+func leadingComments(tokens []genericinterperter.Tokener) []genericinterperter.Tokener {
+	var ret []genericinterperter.Tokener
+	for _, t := range tokens {
+		// the type of a declaration is the type of its first token.
+		x, ok := t.(genericinterperter.Expressioner)
+		if _, isToken := t.(*genericinterperter.TokenWithPos); isToken || !ok {
+			if t.GetType() == genericlexer.CommentLineToken {
+				ret = append(ret, t)
+			}
+			continue
+		}
+		for _, c := range x.GetTokens() {
+			if c.GetType() == genericlexer.CommentLineToken {
+				ret = append(ret, c)
+			} else if c.GetType() != glanglexer.NlToken && c.GetType() != genericlexer.WsToken {
+				break
+			}
+		}
+	}
+	return ret
+}
+
 type slugamer interface {
 	GetSlugName() string
 }
@@ -239,6 +344,9 @@ type StructDecl struct {
 	Name    *IdentifierDecl
 	Methods []FuncDeclarer
 	Block   *PropsBlockDecl
+	Pkg     string // name of the package a struct was loaded from, empty for local structs.
+
+	TypeParams *PropsBlockDecl // nil unless the struct is generic, [T any].
 }
 
 func (p *StructDecl) GetBlock() genericinterperter.Expressioner {
@@ -254,6 +362,15 @@ func (p *StructDecl) AddMethod(f FuncDeclarer) {
 	p.Methods = append(p.Methods, f)
 }
 
+// GetQualifiedName returns the name of the struct prefixed with its package,
+// pkg.Name if it was loaded from another package, Name otherwise.
+func (p *StructDecl) GetQualifiedName() string {
+	if p.Pkg == "" {
+		return p.GetName()
+	}
+	return p.Pkg + "." + p.GetName()
+}
+
 // NewStructDecl creates a new StructDecl
 func NewStructDecl() *StructDecl {
 	return &StructDecl{}
@@ -262,10 +379,37 @@ func NewStructDecl() *StructDecl {
 type TemplateDecl struct {
 	genericinterperter.Expression
 	Name    *IdentifierDecl
+	Params  *PropsBlockDecl // nil unless the template declares its params.
 	Methods []FuncDeclarer
+	Extras  []*TemplateExtraDecl // the declarations emitted along the type.
 	Block   *PropsBlockDecl
 }
 
+// AddExtra attaches a template(X) declaration to the template.
+func (t *TemplateDecl) AddExtra(e *TemplateExtraDecl) {
+	t.Extras = append(t.Extras, e)
+}
+
+// GetParams returns the declared params of the template,
+// template <:.Name>Slice(keys ...field, sorted bool) struct{}.
+func (t *TemplateDecl) GetParams() []*Param {
+	return resolveParams(t.Params, "arg")
+}
+
+// RemoveParams removes the declaration of the params,
+// the template can then be written as a type.
+func (t *TemplateDecl) RemoveParams() {
+	if t.Params == nil {
+		return
+	}
+	for _, x := range t.Tokens {
+		if s, ok := x.(*StructDecl); ok {
+			s.Remove(t.Params)
+		}
+	}
+	t.Params = nil
+}
+
 func (t *TemplateDecl) SetDelims(l, r string) {
 	t.SetTokenValue(glanglexer.TplOpenToken, l)
 	t.SetTokenValue(glanglexer.TplCloseToken, r)
@@ -296,10 +440,34 @@ func NewTemplateDecl() *TemplateDecl {
 	return &TemplateDecl{}
 }
 
+// TemplateExtraDecl is a declaration emitted along the type of the template Of,
+// template(Slice) func New<:.Name>Slice() *<:.Name>Slice {}
+// a func, a const, a var or a type.
+type TemplateExtraDecl struct {
+	genericinterperter.Expression
+	Of   *IdentifierDecl
+	Decl genericinterperter.Tokener
+}
+
+func (t *TemplateExtraDecl) String() string {
+	return t.Expression.String()
+}
+
+// GetOf returns the name of the template.
+func (t *TemplateExtraDecl) GetOf() string {
+	return t.Of.String()
+}
+
+// NewTemplateExtraDecl creates a new TemplateExtraDecl
+func NewTemplateExtraDecl() *TemplateExtraDecl {
+	return &TemplateExtraDecl{}
+}
+
 type InterfaceDecl struct {
 	genericinterperter.Expression
 	Name  *IdentifierDecl
 	Block *SignsBlockDecl
+	Pkg   string // name of the package an interface was loaded from, empty for local interfaces.
 }
 
 func (p *InterfaceDecl) String() string {
@@ -309,6 +477,28 @@ func (p *InterfaceDecl) GetName() string {
 	return p.Name.GetValue()
 }
 
+// GetQualifiedName returns the name of the interface prefixed with its package,
+// pkg.Name if it was loaded from another package, Name otherwise.
+func (p *InterfaceDecl) GetQualifiedName() string {
+	if p.Pkg == "" {
+		return p.GetName()
+	}
+	return p.Pkg + "." + p.GetName()
+}
+
+// GetMethods returns the signatures declared by the interface,
+// the methods of its embedded interfaces are not resolved.
+func (p *InterfaceDecl) GetMethods() []FuncDeclarer {
+	ret := []FuncDeclarer{}
+	if p.Block == nil {
+		return ret
+	}
+	for _, s := range p.Block.Signs {
+		ret = append(ret, s)
+	}
+	return ret
+}
+
 // NewInterfaceDecl creates a new InterfaceDecl
 func NewInterfaceDecl() *InterfaceDecl {
 	return &InterfaceDecl{}
@@ -318,6 +508,7 @@ type ImplementDecl struct {
 	genericinterperter.Expression
 	Name              *IdentifierDecl
 	ImplementTemplate genericinterperter.Tokener
+	Chains            []*ImplementChain // the template expressions, their results are embedded.
 	Methods           []FuncDeclarer
 }
 
@@ -390,7 +581,9 @@ func (t *TemplateFuncDecl) IsMethod() bool {
 	return t.Func.IsMethod()
 }
 func (t *TemplateFuncDecl) IsDefine() bool {
-	return t.Modifier.String() == "<define>"
+	// the modifier is written <:define> in a gigo file.
+	m := strings.TrimPrefix(t.Modifier.String(), "<:")
+	return strings.TrimSpace(strings.TrimSuffix(m, ">")) == "define"
 }
 func (p *TemplateFuncDecl) String() string {
 	return p.Expression.String()
@@ -416,6 +609,9 @@ func (t *TemplateFuncDecl) GetArgsBlock() []*PropDecl {
 func (t *TemplateFuncDecl) GetArgsNames() []*IdentifierDecl {
 	return t.Func.GetArgsNames()
 }
+func (t *TemplateFuncDecl) GetOut() *PropsBlockDecl {
+	return t.Func.GetOut()
+}
 
 // FuncDeclarer is a func or a template func
 type FuncDeclarer interface {
@@ -432,6 +628,7 @@ type FuncDeclarer interface {
 	GetPos() genericinterperter.TokenPos
 	GetArgsBlock() []*PropDecl
 	GetArgsNames() []*IdentifierDecl
+	GetOut() *PropsBlockDecl
 }
 
 type FuncDecl struct {
@@ -456,6 +653,9 @@ func (p *FuncDecl) GetArgsNames() []*IdentifierDecl {
 	}
 	return ret
 }
+func (p *FuncDecl) GetOut() *PropsBlockDecl {
+	return p.Out
+}
 func (p *FuncDecl) GetBody() *BodyBlockDecl {
 	return p.Body
 }
@@ -511,9 +711,10 @@ func NewSignsBlockDecl() *SignsBlockDecl {
 
 type PropsBlockDecl struct {
 	genericinterperter.Expression
-	Poireaux   []*PoireauDecl
-	Underlying []*ExpressionDecl
-	Props      []*PropDecl
+	Poireaux       []*PoireauDecl
+	Underlying     []*ExpressionDecl
+	Props          []*PropDecl
+	underlyingTags map[*ExpressionDecl]*TagDecl
 }
 
 func (p *PropsBlockDecl) String() string {
@@ -525,6 +726,22 @@ func (p *PropsBlockDecl) AddUnderlying(Type *ExpressionDecl) {
 	p.Underlying = append(p.Underlying, Type)
 }
 
+// SetUnderlyingTag attaches a tag to an underlying type of the block.
+func (p *PropsBlockDecl) SetUnderlyingTag(Type *ExpressionDecl, tag *TagDecl) {
+	if tag == nil {
+		return
+	}
+	if p.underlyingTags == nil {
+		p.underlyingTags = map[*ExpressionDecl]*TagDecl{}
+	}
+	p.underlyingTags[Type] = tag
+}
+
+// GetUnderlyingTag returns the tag of an underlying type, or nil.
+func (p *PropsBlockDecl) GetUnderlyingTag(Type *ExpressionDecl) *TagDecl {
+	return p.underlyingTags[Type]
+}
+
 // AddPoireau poireau<> mutation to a struct like body declaration.
 func (p *PropsBlockDecl) AddPoireau(Mutation *PoireauDecl) {
 	p.Poireaux = append(p.Poireaux, Mutation)
@@ -655,6 +872,7 @@ type PropDecl struct {
 	genericinterperter.Expression
 	Name *IdentifierDecl
 	Type *ExpressionDecl
+	Tag  *TagDecl
 }
 
 func (p *PropDecl) String() string {
@@ -670,6 +888,136 @@ func (p *PropDecl) GetPropType() string {
 	return p.Type.GetValue()
 }
 
+// GetTag returns the tag of the property, it can be nil.
+func (p *PropDecl) GetTag() *TagDecl {
+	return p.Tag
+}
+
+// GetStruct returns the anonymous struct declared as the type of the property,
+// such as Meta struct{ Created string }, or nil.
+func (p *PropDecl) GetStruct() *StructDecl {
+	if p.Type == nil {
+		return nil
+	}
+	for _, e := range p.Type.GetExprs() {
+		if x, ok := e.(*StructDecl); ok {
+			return x
+		}
+	}
+	return nil
+}
+
+// IsInlineStruct returns true when the property type is an anonymous struct.
+func (p *PropDecl) IsInlineStruct() bool {
+	return p.GetStruct() != nil
+}
+
+// TagDecl is a struct property tag such as `json:"name"`.
+type TagDecl struct {
+	genericinterperter.Expression
+}
+
+// TagPair is a key:"value" pair of a tag.
+type TagPair struct {
+	Key   string
+	Value string
+}
+
+func (t *TagDecl) String() string {
+	return t.Expression.String()
+}
+
+// GetRaw returns the content of the tag without its quotes.
+func (t *TagDecl) GetRaw() string {
+	if t == nil {
+		return ""
+	}
+	v := t.String()
+	if s, err := strconv.Unquote(v); err == nil {
+		return s
+	}
+	return v
+}
+
+// Pairs parses the tag following the reflect.StructTag conventions.
+func (t *TagDecl) Pairs() []TagPair {
+	ret := []TagPair{}
+	tag := t.GetRaw()
+	for tag != "" {
+		i := 0
+		for i < len(tag) && tag[i] == ' ' {
+			i++
+		}
+		tag = tag[i:]
+		if tag == "" {
+			break
+		}
+		i = 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			break
+		}
+		key := tag[:i]
+		tag = tag[i+1:]
+
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			break
+		}
+		value, err := strconv.Unquote(tag[:i+1])
+		if err != nil {
+			break
+		}
+		tag = tag[i+1:]
+		ret = append(ret, TagPair{Key: key, Value: value})
+	}
+	return ret
+}
+
+// Keys returns the keys of the tag, in order.
+func (t *TagDecl) Keys() []string {
+	ret := []string{}
+	for _, p := range t.Pairs() {
+		ret = append(ret, p.Key)
+	}
+	return ret
+}
+
+// Has returns true if the tag defines key.
+func (t *TagDecl) Has(key string) bool {
+	for _, p := range t.Pairs() {
+		if p.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Lookup returns the value associated with key, or an empty string.
+// Unlike reflect.StructTag.Lookup it returns a single value
+// so it can be invoked from a template <:$p.Tag.Lookup "json":>.
+func (t *TagDecl) Lookup(key string) string {
+	for _, p := range t.Pairs() {
+		if p.Key == key {
+			return p.Value
+		}
+	}
+	return ""
+}
+
+// NewTagDecl creates a new TagDecl
+func NewTagDecl() *TagDecl {
+	return &TagDecl{}
+}
+
 // NewPropDecl creates a new PropDecl
 func NewPropDecl() *PropDecl {
 	return &PropDecl{}
@@ -1013,6 +1361,287 @@ func NewSwitchStmt() *SwitchStmt {
 	return &SwitchStmt{}
 }
 
+// TypeSwitchStmt is a switch on the type of a value
+// switch v := x.(type) {}
+type TypeSwitchStmt struct {
+	genericinterperter.Expression
+	Init   *AssignExpr // v := x.(type), nil when the value is not assigned.
+	Assert *TypeAssertExpr
+	Body   *BranchedStmtBlock
+}
+
+func (p *TypeSwitchStmt) SetInit(x *AssignExpr) {
+	p.Init = x
+}
+func (p *TypeSwitchStmt) GetInit() *AssignExpr {
+	return p.Init
+}
+func (p *TypeSwitchStmt) GetBody() *BodyBlockDecl {
+	return &p.Body.BodyBlockDecl
+}
+func (p *TypeSwitchStmt) GetBranches() *BranchedStmtBlock {
+	return p.Body
+}
+func (p *TypeSwitchStmt) GetBranch(i int) *BranchStmt {
+	if i >= len(p.Body.Branches) {
+		return nil
+	}
+	return p.Body.Branches[i]
+}
+
+// GetVarName returns the name of the value assigned in the switch, v of v := x.(type).
+func (p *TypeSwitchStmt) GetVarName() string {
+	if p.Init == nil || len(p.Init.IDs) == 0 {
+		return ""
+	}
+	return p.Init.IDs[0].GetSlugName()
+}
+func (p *TypeSwitchStmt) String() string {
+	return p.Expression.String()
+}
+
+// NewTypeSwitchStmt creates a new TypeSwitchStmt
+func NewTypeSwitchStmt() *TypeSwitchStmt {
+	return &TypeSwitchStmt{}
+}
+
+// TypeAssertExpr asserts the type of a value
+// x.(T), s[0].(T), f().(T), or x.(type) in a type switch.
+type TypeAssertExpr struct {
+	genericinterperter.Expression
+	X    genericinterperter.Tokener
+	Type *ExpressionDecl // nil for x.(type)
+}
+
+// IsTypeSwitch returns true for x.(type).
+func (p *TypeAssertExpr) IsTypeSwitch() bool {
+	return p.Type == nil
+}
+func (p *TypeAssertExpr) String() string {
+	return p.Expression.String()
+}
+
+// NewTypeAssertExpr creates a new TypeAssertExpr
+func NewTypeAssertExpr() *TypeAssertExpr {
+	return &TypeAssertExpr{}
+}
+
+// FindTypeAssert returns the first TypeAssertExpr of e, or nil.
+func FindTypeAssert(e genericinterperter.Expressioner) *TypeAssertExpr {
+	if e == nil {
+		return nil
+	}
+	for _, x := range e.GetExprs() {
+		if a, ok := x.(*TypeAssertExpr); ok {
+			return a
+		}
+		if a := FindTypeAssert(x); a != nil {
+			return a
+		}
+	}
+	return nil
+}
+
+// LabeledStmt is a statement prefixed by a label
+// outer: for {}
+type LabeledStmt struct {
+	genericinterperter.Expression
+	Label *IdentifierDecl
+	Stmt  genericinterperter.Tokener
+}
+
+// GetLabel returns the name of the label.
+func (p *LabeledStmt) GetLabel() string {
+	return p.Label.GetSlugName()
+}
+func (p *LabeledStmt) String() string {
+	return p.Expression.String()
+}
+
+// NewLabeledStmt creates a new LabeledStmt
+func NewLabeledStmt() *LabeledStmt {
+	return &LabeledStmt{}
+}
+
+// JumpStmt is a goto, break or continue statement,
+// with an optional label
+// break outer
+type JumpStmt struct {
+	genericinterperter.Expression
+	Keyword genericinterperter.Tokener
+	Label   *IdentifierDecl // nil if there is no label.
+}
+
+// GetKeyword returns goto, break or continue.
+func (p *JumpStmt) GetKeyword() string {
+	return p.Keyword.GetValue()
+}
+
+// GetLabel returns the name of the label, or an empty string.
+func (p *JumpStmt) GetLabel() string {
+	if p.Label == nil {
+		return ""
+	}
+	return p.Label.GetSlugName()
+}
+func (p *JumpStmt) String() string {
+	return p.Expression.String()
+}
+
+// NewJumpStmt creates a new JumpStmt
+func NewJumpStmt() *JumpStmt {
+	return &JumpStmt{}
+}
+
+// FuncLit is an anonymous func used as a value
+// f := func(a int) int { return a }
+type FuncLit struct {
+	genericinterperter.Expression
+	Func *FuncDecl
+}
+
+func (p *FuncLit) GetArgs() *PropsBlockDecl {
+	return p.Func.GetArgs()
+}
+func (p *FuncLit) GetOut() *PropsBlockDecl {
+	return p.Func.GetOut()
+}
+func (p *FuncLit) GetBody() *BodyBlockDecl {
+	return p.Func.GetBody()
+}
+func (p *FuncLit) String() string {
+	return p.Expression.String()
+}
+
+// NewFuncLit creates a new FuncLit
+func NewFuncLit() *FuncLit {
+	return &FuncLit{}
+}
+
+// CompositeLit is a composite literal
+// T{a: 1}, []int{1, 2}, &T{} is an UnaryExpr of a CompositeLit.
+type CompositeLit struct {
+	genericinterperter.Expression
+	Type  genericinterperter.Tokener // nil if the type is elided, []T{{a: 1}}.
+	Open  genericinterperter.Tokener
+	Close genericinterperter.Tokener
+	Elts  []genericinterperter.Tokener
+}
+
+func (p *CompositeLit) AddElt(e genericinterperter.Tokener) {
+	p.Elts = append(p.Elts, e)
+}
+
+// GetTypeName returns the type of the literal, or an empty string.
+func (p *CompositeLit) GetTypeName() string {
+	if p.Type == nil {
+		return ""
+	}
+	return strings.TrimSpace(p.Type.String())
+}
+
+// GetElt returns the element at index i, or nil.
+func (p *CompositeLit) GetElt(i int) genericinterperter.Tokener {
+	if i < 0 || i >= len(p.Elts) {
+		return nil
+	}
+	return p.Elts[i]
+}
+func (p *CompositeLit) String() string {
+	return p.Expression.String()
+}
+
+// NewCompositeLit creates a new CompositeLit
+func NewCompositeLit() *CompositeLit {
+	return &CompositeLit{}
+}
+
+// KeyValueExpr is an element of a composite literal, a: 1
+type KeyValueExpr struct {
+	genericinterperter.Expression
+	Key   genericinterperter.Tokener
+	Value genericinterperter.Tokener
+}
+
+// GetKey returns the key of the element.
+func (p *KeyValueExpr) GetKey() string {
+	return strings.TrimSpace(p.Key.String())
+}
+func (p *KeyValueExpr) String() string {
+	return p.Expression.String()
+}
+
+// NewKeyValueExpr creates a new KeyValueExpr
+func NewKeyValueExpr() *KeyValueExpr {
+	return &KeyValueExpr{}
+}
+
+// IndexExpr is a map or a slice indexed by a key, m[k]
+type IndexExpr struct {
+	genericinterperter.Expression
+	X     genericinterperter.Tokener
+	Index *ExpressionDecl
+}
+
+func (p *IndexExpr) String() string {
+	return p.Expression.String()
+}
+
+// NewIndexExpr creates a new IndexExpr
+func NewIndexExpr() *IndexExpr {
+	return &IndexExpr{}
+}
+
+// SliceExpr is a slice expression, s[i:j] or s[i:j:k]
+// Low, High and Max are nil when they are omitted.
+type SliceExpr struct {
+	genericinterperter.Expression
+	X      genericinterperter.Tokener
+	Low    *ExpressionDecl
+	High   *ExpressionDecl
+	Max    *ExpressionDecl
+	Slice3 bool
+}
+
+func (p *SliceExpr) String() string {
+	return p.Expression.String()
+}
+
+// NewSliceExpr creates a new SliceExpr
+func NewSliceExpr() *SliceExpr {
+	return &SliceExpr{}
+}
+
+// UnaryExpr is an operator applied to an operand, -x, !ok, &x, *p, <-ch
+type UnaryExpr struct {
+	genericinterperter.Expression
+	Op genericinterperter.Tokener
+	X  genericinterperter.Tokener
+}
+
+// GetOp returns the operator, such as & or *.
+func (p *UnaryExpr) GetOp() string {
+	return p.Op.GetValue()
+}
+
+// IsAddr returns true for &x.
+func (p *UnaryExpr) IsAddr() bool {
+	return p.GetOp() == "&"
+}
+
+// IsDeref returns true for *p.
+func (p *UnaryExpr) IsDeref() bool {
+	return p.GetOp() == "*"
+}
+func (p *UnaryExpr) String() string {
+	return p.Expression.String()
+}
+
+// NewUnaryExpr creates a new UnaryExpr
+func NewUnaryExpr() *UnaryExpr {
+	return &UnaryExpr{}
+}
+
 type BlockBrancher interface {
 	GetBranches() *BranchedStmtBlock
 	GetBranch(int) *BranchStmt
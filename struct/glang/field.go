@@ -0,0 +1,106 @@
+package glang
+
+// Field is a property of a struct, embedded or not.
+// It is the view given to templates when they range over .Props.
+type Field struct {
+	Name     string
+	Type     *TypeRef
+	Tag      *TagDecl
+	Embedded bool
+	Exported bool
+	Prop     *PropDecl       // nil if the field is embedded.
+	Expr     *ExpressionDecl // the type declaration.
+}
+
+func (f *Field) String() string {
+	if f.Embedded {
+		return f.Type.String()
+	}
+	return f.Name + " " + f.Type.String()
+}
+
+// Lookup the tag of the field for key.
+func (f *Field) Lookup(key string) string {
+	return f.Tag.Lookup(key)
+}
+
+// NewField creates a Field of a named property.
+func NewField(p *PropDecl) *Field {
+	name := p.GetName()
+	return &Field{
+		Name:     name,
+		Type:     NewTypeRef(p.Type.String()),
+		Tag:      p.Tag,
+		Exported: isExported(name),
+		Prop:     p,
+		Expr:     p.Type,
+	}
+}
+
+// NewEmbeddedField creates a Field of an underlying type,
+// its name is the name of the type, T for *pkg.T.
+func NewEmbeddedField(Type *ExpressionDecl, tag *TagDecl) *Field {
+	t := NewTypeRef(Type.String())
+	name := t.Base().Name()
+	return &Field{
+		Name:     name,
+		Type:     t,
+		Tag:      tag,
+		Embedded: true,
+		Exported: isExported(name),
+		Expr:     Type,
+	}
+}
+
+// GetFields returns the properties and the underlying types of the block,
+// in their order of declaration.
+func (p *PropsBlockDecl) GetFields() []*Field {
+	ret := []*Field{}
+	for _, e := range p.GetExprs() {
+		if x, ok := e.(*PropDecl); ok && x.Name != nil {
+			ret = append(ret, NewField(x))
+		} else if x, ok := e.(*ExpressionDecl); ok && p.isUnderlying(x) {
+			ret = append(ret, NewEmbeddedField(x, p.GetUnderlyingTag(x)))
+		}
+	}
+	return ret
+}
+
+// GetField returns the field of given name, or nil.
+func (p *PropsBlockDecl) GetField(name string) *Field {
+	for _, f := range p.GetFields() {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+func (p *PropsBlockDecl) isUnderlying(x *ExpressionDecl) bool {
+	for _, u := range p.Underlying {
+		if u == x {
+			return true
+		}
+	}
+	return false
+}
+
+// GetFields returns the fields of the struct.
+func (p *StructDecl) GetFields() []*Field {
+	if p.Block == nil {
+		return []*Field{}
+	}
+	return p.Block.GetFields()
+}
+
+// GetField returns the field of given name, or nil.
+func (p *StructDecl) GetField(name string) *Field {
+	if p.Block == nil {
+		return nil
+	}
+	return p.Block.GetField(name)
+}
+
+func isExported(name string) bool {
+	return name != "" && name[0] >= 'A' && name[0] <= 'Z'
+}
@@ -0,0 +1,210 @@
+package glang
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Param is a parameter or a result of a func.
+type Param struct {
+	Name     string
+	Type     *TypeRef
+	Variadic bool
+	// Generated is true when the name was not declared in the source.
+	Generated bool
+}
+
+func (p *Param) String() string {
+	t := p.Type.String()
+	if p.Variadic {
+		t = "..." + t
+	}
+	return p.Name + " " + t
+}
+
+// MethodView is the view of a func given to templates when they range over .Methods.
+type MethodView struct {
+	FuncDeclarer
+	Name string
+}
+
+// NewMethodView creates a MethodView of f.
+func NewMethodView(f FuncDeclarer) *MethodView {
+	return &MethodView{FuncDeclarer: f, Name: f.GetName()}
+}
+
+// Out returns the results block of the func, it can be nil.
+func (m *MethodView) Out() *PropsBlockDecl {
+	return m.GetOut()
+}
+
+// Params returns the parameters of the func,
+// unnamed, blank and reserved parameters are given a name such as arg0.
+func (m *MethodView) Params() []*Param {
+	return renameParams(resolveParams(m.GetArgs(), "arg"), "arg")
+}
+
+// Results returns the results of the func,
+// unnamed, blank and reserved results are given a name such as res0.
+func (m *MethodView) Results() []*Param {
+	return renameParams(resolveParams(m.GetOut(), "res"), "res")
+}
+
+// ResultTypes returns the type of each result.
+func (m *MethodView) ResultTypes() []string {
+	ret := []string{}
+	for _, r := range m.Results() {
+		ret = append(ret, r.Type.String())
+	}
+	return ret
+}
+
+// HasResults returns true if the func returns at least one value.
+func (m *MethodView) HasResults() bool {
+	return len(m.Results()) > 0
+}
+
+// HasError returns true if the last result of the func is an error.
+func (m *MethodView) HasError() bool {
+	r := m.Results()
+	return len(r) > 0 && r[len(r)-1].Type.String() == "error"
+}
+
+// IsPointerReceiver returns true for a method declared on *T.
+func (m *MethodView) IsPointerReceiver() bool {
+	return m.IsMethod() && m.ReceiverType().IsPointer()
+}
+
+// ReceiverType returns the type of the receiver, or nil.
+func (m *MethodView) ReceiverType() *TypeRef {
+	if !m.IsMethod() {
+		return nil
+	}
+	return NewTypeRef(m.GetReceiverType().String())
+}
+
+// IsVariadic returns true if the last parameter of the func is ...T.
+func (m *MethodView) IsVariadic() bool {
+	p := m.Params()
+	return len(p) > 0 && p[len(p)-1].Variadic
+}
+
+// ParamsDecl returns the parameters to declare the func, a int, b ...string.
+func (m *MethodView) ParamsDecl() string {
+	return joinParams(m.Params(), func(p *Param) string { return p.String() })
+}
+
+// CallArgs returns the arguments to call the func, a, b...
+func (m *MethodView) CallArgs() string {
+	return joinParams(m.Params(), func(p *Param) string {
+		if p.Variadic {
+			return p.Name + "..."
+		}
+		return p.Name
+	})
+}
+
+// ResultsDecl returns the results to declare the func, int or (int, error).
+func (m *MethodView) ResultsDecl() string {
+	r := m.ResultTypes()
+	if len(r) == 1 {
+		return r[0]
+	}
+	if len(r) == 0 {
+		return ""
+	}
+	return "(" + strings.Join(r, ", ") + ")"
+}
+
+// NamedResultsDecl returns the named results to declare the func, (res0 int, err error).
+func (m *MethodView) NamedResultsDecl() string {
+	if !m.HasResults() {
+		return ""
+	}
+	return "(" + joinParams(m.Results(), func(p *Param) string { return p.String() }) + ")"
+}
+
+// ReturnList returns the names of the results, res0, err.
+func (m *MethodView) ReturnList() string {
+	return joinParams(m.Results(), func(p *Param) string { return p.Name })
+}
+
+// AssignResults returns the left side of an assignment of the results, res0, err = ,
+// it is empty if the func has no results.
+func (m *MethodView) AssignResults() string {
+	if !m.HasResults() {
+		return ""
+	}
+	return m.ReturnList() + " = "
+}
+
+// reservedNames are the names of the receiver and of the locals of the wrappers
+// written by the std templates, a param can not be named after them.
+var reservedNames = map[string]bool{
+	"m":     true,
+	"embed": true,
+	"done":  true,
+}
+
+// resolveParams reads a params block,
+// in (a, b int) a is read as an unnamed prop, its type is the type of b.
+func resolveParams(block *PropsBlockDecl, prefix string) []*Param {
+	ret := []*Param{}
+	if block == nil {
+		return ret
+	}
+	named := false
+	for _, p := range block.Props {
+		if p.Name != nil {
+			named = true
+		}
+	}
+	pending := []*Param{}
+	for i, p := range block.Props {
+		t := strings.TrimSpace(p.Type.String())
+		param := &Param{}
+		if strings.HasPrefix(t, "...") {
+			param.Variadic = true
+			t = t[3:]
+		}
+		if named && p.Name == nil {
+			param.Name = t
+			pending = append(pending, param)
+			ret = append(ret, param)
+			continue
+		}
+		param.Type = NewTypeRef(t)
+		if p.Name != nil {
+			param.Name = p.GetName()
+		} else {
+			param.Name = fmt.Sprintf("%v%v", prefix, i)
+			param.Generated = true
+		}
+		for _, x := range pending {
+			x.Type = param.Type
+		}
+		pending = pending[:0]
+		ret = append(ret, param)
+	}
+	return ret
+}
+
+// renameParams renames the params named _ or after a reserved name,
+// so they can be given to a call or returned.
+func renameParams(params []*Param, prefix string) []*Param {
+	for i, p := range params {
+		if p.Name == "_" || reservedNames[p.Name] {
+			p.Name = fmt.Sprintf("%v%v", prefix, i)
+			p.Generated = true
+		}
+	}
+	return params
+}
+
+func joinParams(params []*Param, f func(*Param) string) string {
+	ret := []string{}
+	for _, p := range params {
+		ret = append(ret, f(p))
+	}
+	return strings.Join(ret, ", ")
+}
@@ -18,11 +18,13 @@ type ScopeReceiver interface {
 	FindPackagesDecl() []*PackageDecl
 	FindImplementsTypes() []*ImplementDecl
 	FindStructsTypes() []*StructDecl
+	FindInterfaces() []*InterfaceDecl
 	FindTemplatesTypes() []*TemplateDecl
 	FindFuncs() []*FuncDecl
 	FindTemplateFuncs() []FuncDeclarer
 	FindDefineFuncs() []*TemplateFuncDecl
 	FindSymbols(string) []genericinterperter.Expressioner
+	Select(string) ([]genericinterperter.Expressioner, error)
 	String() string
 }
 
@@ -57,6 +59,15 @@ func (p *Package) FindStructsTypes() []*StructDecl {
 	return ret
 }
 
+// FindInterfaces returns all interface declarations found.
+func (p *Package) FindInterfaces() []*InterfaceDecl {
+	var ret []*InterfaceDecl
+	for _, f := range p.Files {
+		ret = append(ret, f.FindInterfaces()...)
+	}
+	return ret
+}
+
 // FindTemplatesTypes returns all template declarations found.
 func (p *Package) FindTemplatesTypes() []*TemplateDecl {
 	var ret []*TemplateDecl
@@ -0,0 +1,254 @@
+package glang
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	genericinterperter "github.com/mh-cbon/gigo/interpreter/generic"
+)
+
+// Selector is a query of declarations such as
+// Todos.Push, func:*Slice.Remove*, template:Mutexed, struct:Todo/field:Name.
+//
+// Steps are separated by /, the first step selects among the top level declarations,
+// the next ones select among the fields and the methods of the previous results.
+// A step is written kind:pattern, the kind is optional.
+// Patterns are matched with path.Match, Recv.Name matches the methods Name of the type Recv.
+type Selector []SelectorStep
+
+// SelectorStep is a step of a Selector.
+type SelectorStep struct {
+	Kind string // one of SelectorKinds, or empty to select any kind.
+	Recv string // pattern of the receiver type of a method, or empty.
+	Name string // pattern of the name.
+}
+
+// SelectorKinds are the kinds a step can select.
+var SelectorKinds = []string{"type", "struct", "template", "interface", "impl", "func", "field", "var", "const"}
+
+var methodPattern = regexp.MustCompile(`^(.+)\.([A-Za-z0-9_*?]+)$`)
+
+// ParseSelector parses a selector.
+func ParseSelector(s string) (Selector, error) {
+	ret := Selector{}
+	for _, part := range strings.Split(s, "/") {
+		step := SelectorStep{Name: strings.TrimSpace(part)}
+		if i := strings.Index(step.Name, ":"); i > -1 && isSelectorKind(step.Name[:i]) {
+			step.Kind = step.Name[:i]
+			step.Name = step.Name[i+1:]
+		}
+		if m := methodPattern.FindStringSubmatch(step.Name); m != nil && !strings.Contains(m[1], "<") {
+			step.Recv = m[1]
+			step.Name = m[2]
+		}
+		if step.Name == "" {
+			return nil, fmt.Errorf("invalid selector %q, empty step", s)
+		}
+		for _, p := range []string{step.Recv, step.Name} {
+			if _, err := path.Match(p, ""); err != nil {
+				return nil, fmt.Errorf("invalid selector %q, %v", s, err)
+			}
+		}
+		ret = append(ret, step)
+	}
+	return ret, nil
+}
+
+func isSelectorKind(k string) bool {
+	for _, x := range SelectorKinds {
+		if x == k {
+			return true
+		}
+	}
+	return false
+}
+
+// Match returns the declarations that matches the selector,
+// decls are the top level declarations to search in.
+func (s Selector) Match(decls []genericinterperter.Expressioner) []genericinterperter.Expressioner {
+	ret := []genericinterperter.Expressioner{}
+	if len(s) == 0 {
+		return ret
+	}
+	for _, d := range decls {
+		if s[0].matchTop(d) {
+			ret = append(ret, d)
+		}
+		if s[0].Kind == "var" || s[0].Kind == "const" || s[0].Kind == "" {
+			ret = append(ret, s[0].matchAssigns(d)...)
+		}
+	}
+	for _, step := range s[1:] {
+		children := []genericinterperter.Expressioner{}
+		for _, parent := range ret {
+			children = append(children, step.matchChildren(parent, decls)...)
+		}
+		ret = children
+	}
+	return ret
+}
+
+func (s SelectorStep) matchTop(d genericinterperter.Expressioner) bool {
+	if s.Recv != "" {
+		f, ok := d.(FuncDeclarer)
+		return ok && s.isKind("func") && s.matchFunc(f)
+	}
+	switch x := d.(type) {
+	case *PackageDecl:
+		return s.Kind == "" && s.matchName(x.GetName())
+	case *StructDecl:
+		return s.isKind("type", "struct") && s.matchName(x.GetName())
+	case *TemplateDecl:
+		return s.isKind("type", "template") && s.matchName(x.GetSlugName())
+	case *InterfaceDecl:
+		return s.isKind("type", "interface") && s.matchName(x.GetName())
+	case *ImplementDecl:
+		return s.isKind("type", "impl") && s.matchName(x.GetName())
+	case FuncDeclarer:
+		return s.isKind("func") && s.matchFunc(x)
+	}
+	return false
+}
+
+func (s SelectorStep) matchAssigns(d genericinterperter.Expressioner) []genericinterperter.Expressioner {
+	ret := []genericinterperter.Expressioner{}
+	var assigns []*AssignDecl
+	if x, ok := d.(*VarDecl); ok && s.isKind("var") {
+		assigns = x.GetAssignments()
+	} else if x, ok := d.(*ConstDecl); ok && s.isKind("const") {
+		assigns = x.GetAssignments()
+	}
+	for _, a := range assigns {
+		if s.Recv == "" && s.matchName(a.GetLeft()) {
+			ret = append(ret, a)
+		}
+	}
+	return ret
+}
+
+func (s SelectorStep) matchChildren(parent genericinterperter.Expressioner, decls []genericinterperter.Expressioner) []genericinterperter.Expressioner {
+	ret := []genericinterperter.Expressioner{}
+	var name string
+	var fields []*Field
+	var methods []FuncDeclarer
+	switch x := parent.(type) {
+	case *StructDecl:
+		name, fields, methods = x.GetName(), x.GetFields(), x.Methods
+	case *TemplateDecl:
+		name, methods = strings.TrimSpace(x.GetName()), x.Methods
+		if x.Block != nil {
+			fields = x.Block.GetFields()
+		}
+	case *ImplementDecl:
+		name, methods = x.GetName(), x.Methods
+		if x.GetBlock() != nil {
+			fields = x.GetBlock().GetFields()
+		}
+	case *InterfaceDecl:
+		name = x.GetName()
+		if x.Block != nil {
+			for _, f := range x.Block.Signs {
+				methods = append(methods, f)
+			}
+		}
+	default:
+		return ret
+	}
+
+	if s.isKind("field") && s.Recv == "" {
+		for _, f := range fields {
+			if s.matchName(f.Name) {
+				if f.Prop != nil {
+					ret = append(ret, f.Prop)
+				} else {
+					ret = append(ret, f.Expr)
+				}
+			}
+		}
+	}
+	if s.isKind("func") {
+		// methods declared in the scope, not attached yet to their type.
+		for _, d := range decls {
+			if f, ok := d.(FuncDeclarer); ok && f.IsMethod() && receiverName(f) == name {
+				methods = appendFunc(methods, f)
+			}
+		}
+		for _, f := range methods {
+			if s.matchFunc(f) {
+				ret = append(ret, f)
+			}
+		}
+	}
+	return ret
+}
+
+func (s SelectorStep) isKind(kinds ...string) bool {
+	if s.Kind == "" {
+		return true
+	}
+	for _, k := range kinds {
+		if k == s.Kind {
+			return true
+		}
+	}
+	return false
+}
+
+func (s SelectorStep) matchName(name string) bool {
+	ok, _ := path.Match(s.Name, strings.TrimSpace(name))
+	return ok
+}
+
+func (s SelectorStep) matchFunc(f FuncDeclarer) bool {
+	if !s.matchName(f.GetName()) {
+		return false
+	}
+	if s.Recv == "" {
+		return true
+	}
+	if !f.IsMethod() {
+		return false
+	}
+	ok, _ := path.Match(s.Recv, receiverName(f))
+	return ok
+}
+
+// receiverName is the receiver type of a method without its pointer, T of *T.
+func receiverName(f FuncDeclarer) string {
+	return strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(f.GetReceiverType().String()), "*"))
+}
+
+func appendFunc(funcs []FuncDeclarer, f FuncDeclarer) []FuncDeclarer {
+	for _, x := range funcs {
+		if x == f {
+			return funcs
+		}
+	}
+	return append(funcs, f)
+}
+
+// Select returns the declarations that matches the selector, see Selector.
+func (f *ScopeDecl) Select(selector string) ([]genericinterperter.Expressioner, error) {
+	s, err := ParseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	return s.Match(f.GetExprs()), nil
+}
+
+// Select returns the declarations of all the files that matches the selector, see Selector.
+func (p *Package) Select(selector string) ([]genericinterperter.Expressioner, error) {
+	s, err := ParseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	decls := []genericinterperter.Expressioner{}
+	for _, f := range p.Files {
+		if x, ok := f.(genericinterperter.Expressioner); ok {
+			decls = append(decls, x.GetExprs()...)
+		}
+	}
+	return s.Match(decls), nil
+}
@@ -0,0 +1,192 @@
+package glang
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TypeRef is a type expression such as []*pkg.Name,
+// it helps templates to inspect the declared type of a property.
+type TypeRef struct {
+	expr string
+}
+
+// NewTypeRef creates a new TypeRef of given type expression.
+func NewTypeRef(expr string) *TypeRef {
+	return &TypeRef{expr: strings.TrimSpace(expr)}
+}
+
+var builtinTypes = map[string]bool{
+	"bool": true, "string": true, "error": true, "byte": true, "rune": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true, "uintptr": true,
+	"float32": true, "float64": true, "complex64": true, "complex128": true,
+}
+
+func (t *TypeRef) String() string {
+	if t == nil {
+		return ""
+	}
+	return t.expr
+}
+
+// Kind returns one of pointer, slice, array, map, chan, func, struct, interface, named.
+func (t *TypeRef) Kind() string {
+	e := t.String()
+	switch {
+	case strings.HasPrefix(e, "*"):
+		return "pointer"
+	case strings.HasPrefix(e, "[]"):
+		return "slice"
+	case strings.HasPrefix(e, "["):
+		return "array"
+	case strings.HasPrefix(e, "map["):
+		return "map"
+	case hasKeyword(e, "chan"), strings.HasPrefix(e, "<-"):
+		return "chan"
+	case hasKeyword(e, "func"):
+		return "func"
+	case hasKeyword(e, "struct"):
+		return "struct"
+	case hasKeyword(e, "interface"):
+		return "interface"
+	}
+	return "named"
+}
+
+// hasKeyword returns true if e starts with the keyword kw followed by a token,
+// chan int and chan<- int do, channelID does not.
+func hasKeyword(e, kw string) bool {
+	if !strings.HasPrefix(e, kw) || len(e) == len(kw) {
+		return false
+	}
+	r, _ := utf8.DecodeRuneInString(e[len(kw):])
+	return !(r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+// IsPointer returns true for *T.
+func (t *TypeRef) IsPointer() bool { return t.Kind() == "pointer" }
+
+// IsSlice returns true for []T.
+func (t *TypeRef) IsSlice() bool { return t.Kind() == "slice" }
+
+// IsArray returns true for [N]T.
+func (t *TypeRef) IsArray() bool { return t.Kind() == "array" }
+
+// IsMap returns true for map[K]V.
+func (t *TypeRef) IsMap() bool { return t.Kind() == "map" }
+
+// IsChan returns true for chan T, <-chan T, chan<- T.
+func (t *TypeRef) IsChan() bool { return t.Kind() == "chan" }
+
+// IsFunc returns true for func(...)...
+func (t *TypeRef) IsFunc() bool { return t.Kind() == "func" }
+
+// IsStruct returns true for an anonymous struct{...}.
+func (t *TypeRef) IsStruct() bool { return t.Kind() == "struct" }
+
+// IsInterface returns true for an anonymous interface{...}.
+func (t *TypeRef) IsInterface() bool { return t.Kind() == "interface" }
+
+// IsNamed returns true for a named type such as pkg.T, T or string.
+func (t *TypeRef) IsNamed() bool { return t.Kind() == "named" }
+
+// IsBuiltin returns true for a predeclared type such as string, int.
+func (t *TypeRef) IsBuiltin() bool {
+	return t.IsNamed() && builtinTypes[t.String()]
+}
+
+// IsExported returns true if the named type is exported.
+func (t *TypeRef) IsExported() bool {
+	return isExported(t.Name())
+}
+
+// Pkg returns the package qualifier of a named type, pkg for pkg.T.
+func (t *TypeRef) Pkg() string {
+	if !t.IsNamed() {
+		return ""
+	}
+	if i := strings.LastIndex(t.String(), "."); i > -1 {
+		return t.String()[:i]
+	}
+	return ""
+}
+
+// Name returns the name of a named type, T for pkg.T.
+func (t *TypeRef) Name() string {
+	if !t.IsNamed() {
+		return ""
+	}
+	e := t.String()
+	if i := strings.LastIndex(e, "."); i > -1 {
+		return e[i+1:]
+	}
+	return e
+}
+
+// Elem returns the element type of a pointer, slice, array, map or chan.
+func (t *TypeRef) Elem() *TypeRef {
+	e := t.String()
+	switch t.Kind() {
+	case "pointer":
+		return NewTypeRef(e[1:])
+	case "slice":
+		return NewTypeRef(e[2:])
+	case "array", "map":
+		if i := matchBracket(e, strings.Index(e, "[")); i > -1 {
+			return NewTypeRef(e[i+1:])
+		}
+	case "chan":
+		e = strings.TrimPrefix(e, "<-")
+		e = strings.TrimPrefix(e, "chan")
+		e = strings.TrimPrefix(e, "<-")
+		return NewTypeRef(e)
+	}
+	return nil
+}
+
+// Key returns the key type of a map.
+func (t *TypeRef) Key() *TypeRef {
+	if !t.IsMap() {
+		return nil
+	}
+	e := t.String()
+	if i := matchBracket(e, 3); i > -1 {
+		return NewTypeRef(e[4:i])
+	}
+	return nil
+}
+
+// Base strips pointers, slices, arrays and chans until it finds the underlying type.
+// Base of []*pkg.T is pkg.T.
+func (t *TypeRef) Base() *TypeRef {
+	ret := t
+	for {
+		switch ret.Kind() {
+		case "pointer", "slice", "array", "chan":
+			ret = ret.Elem()
+			continue
+		}
+		return ret
+	}
+}
+
+// matchBracket returns the index of the ] matching the [ at open.
+func matchBracket(s string, open int) int {
+	if open < 0 || open >= len(s) || s[open] != '[' {
+		return -1
+	}
+	count := 0
+	for i := open; i < len(s); i++ {
+		if s[i] == '[' {
+			count++
+		} else if s[i] == ']' {
+			count--
+			if count == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
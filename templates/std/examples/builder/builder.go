@@ -0,0 +1,32 @@
+package builder
+
+//gigo:use std "github.com/mh-cbon/gigo/templates/std"
+
+type Todo struct {
+	Name string
+	Done bool
+}
+
+// Builder builds a value of a type field by field.
+type TodoBuilder struct {
+	value Todo
+}
+
+// With sets a field, for every field.
+func (b *TodoBuilder) WithName(v string) *TodoBuilder {
+	b.value.Name = v
+	return b
+}
+func (b *TodoBuilder) WithDone(v bool) *TodoBuilder {
+	b.value.Done = v
+	return b
+}
+
+// Build returns the value.
+func (b *TodoBuilder) Build() Todo {
+	return b.value
+}
+
+type NewTodo struct {
+	TodoBuilder
+}
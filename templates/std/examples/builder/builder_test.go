@@ -0,0 +1,12 @@
+package builder
+
+import "testing"
+
+func TestNewTodo(t *testing.T) {
+	b := &NewTodo{}
+	got := b.WithName("tomate").WithDone(true).Build()
+	want := Todo{Name: "tomate", Done: true}
+	if got != want {
+		t.Errorf("unexpected todo wanted=%v, got=%v", want, got)
+	}
+}
@@ -0,0 +1,91 @@
+package chanmuxer
+
+//gigo:use std "github.com/mh-cbon/gigo/templates/std"
+
+type Account struct {
+	balance int
+}
+
+func (a *Account) Deposit(amount int) int {
+	a.balance += amount
+	return a.balance
+}
+
+func (a *Account) Withdraw(amount int) (int, bool) {
+	if amount > a.balance {
+		return a.balance, false
+	}
+	a.balance -= amount
+	return a.balance, true
+}
+
+// Transfer names its params after the receiver and the locals of the wrapper,
+// and leaves some blank, the wrapper renames them.
+func (a *Account) Transfer(m int, embed bool, _ string) (done int, _ error) {
+	if embed {
+		a.balance -= m
+	}
+	return a.balance, nil
+}
+
+// ChanMuxer calls the methods of a type from a single goroutine, see Start.
+type AccountChanMuxer struct {
+	ops  chan func(*Account)
+	stop chan bool
+}
+
+// the calls are sent to the goroutine, they wait for their results.
+func (m *AccountChanMuxer) Deposit(amount int) (res0 int) {
+	done := make(chan bool)
+	m.ops <- func(embed *Account) {
+		res0 = embed.Deposit(amount)
+		done <- true
+	}
+	<-done
+	return res0
+}
+func (m *AccountChanMuxer) Withdraw(amount int) (res0 int, res1 bool) {
+	done := make(chan bool)
+	m.ops <- func(embed *Account) {
+		res0, res1 = embed.Withdraw(amount)
+		done <- true
+	}
+	<-done
+	return res0, res1
+}
+func (m *AccountChanMuxer) Transfer(arg0 int, arg1 bool, arg2 string) (res0 int, res1 error) {
+	done := make(chan bool)
+	m.ops <- func(embed *Account) {
+		res0, res1 = embed.Transfer(arg0, arg1, arg2)
+		done <- true
+	}
+	<-done
+	return res0, res1
+}
+
+// Start starts the goroutine that owns embed.
+func (m *AccountChanMuxer) Start(embed *Account) {
+	m.ops = make(chan func(*Account))
+	m.stop = make(chan bool)
+	go m.loop(embed)
+}
+
+func (m *AccountChanMuxer) loop(embed *Account) {
+	for {
+		select {
+		case op := <-m.ops:
+			op(embed)
+		case <-m.stop:
+			return
+		}
+	}
+}
+
+// Stop stops the goroutine, it waits for the pending call.
+func (m *AccountChanMuxer) Stop() {
+	m.stop <- true
+}
+
+type Bank struct {
+	AccountChanMuxer
+}
@@ -0,0 +1,32 @@
+package chanmuxer
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestBank(t *testing.T) {
+	b := &Bank{}
+	b.Start(&Account{})
+	defer b.Stop()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b.Deposit(2)
+		}()
+	}
+	wg.Wait()
+
+	if got, ok := b.Withdraw(1000); ok || got != 100 {
+		t.Errorf("unexpected withdraw wanted=%v, got=%v %v", 100, got, ok)
+	}
+	if got, ok := b.Withdraw(40); !ok || got != 60 {
+		t.Errorf("unexpected withdraw wanted=%v, got=%v %v", 60, got, ok)
+	}
+	if got, err := b.Transfer(10, true, "rent"); err != nil || got != 50 {
+		t.Errorf("unexpected transfer wanted=%v, got=%v %v", 50, got, err)
+	}
+}
@@ -0,0 +1,42 @@
+package mutexed
+
+import "sync"
+
+//gigo:use std "github.com/mh-cbon/gigo/templates/std"
+
+type Counter struct {
+	n int
+}
+
+func (c *Counter) Inc(by int) int {
+	c.n += by
+	return c.n
+}
+
+func (c Counter) Value() int {
+	return c.n
+}
+
+// Mutexed guards a type with a sync.RWMutex.
+type CounterMutexed struct {
+	lock  sync.RWMutex
+	embed Counter
+}
+
+// methods of T take a read lock, methods of *T take a write lock.
+func (m *CounterMutexed) Inc(by int) (res0 int) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	res0 = m.embed.Inc(by)
+	return res0
+}
+func (m *CounterMutexed) Value() (res0 int) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	res0 = m.embed.Value()
+	return res0
+}
+
+type SafeCounter struct {
+	CounterMutexed
+}
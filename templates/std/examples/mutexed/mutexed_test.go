@@ -0,0 +1,23 @@
+package mutexed
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestSafeCounter(t *testing.T) {
+	c := &SafeCounter{}
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.Inc(2)
+			c.Value()
+		}()
+	}
+	wg.Wait()
+	if got := c.Value(); got != 100 {
+		t.Errorf("unexpected value wanted=%v, got=%v", 100, got)
+	}
+}
@@ -0,0 +1,44 @@
+package observable
+
+//gigo:use std "github.com/mh-cbon/gigo/templates/std"
+
+type Todo struct {
+	Name string
+	Done bool
+}
+
+// Observable notifies the listeners of the changes of the fields of a type.
+type TodoObservable struct {
+	value     Todo
+	listeners []func(field string, prev, next interface{})
+}
+
+// setters of the fields, they notify the listeners.
+func (o *TodoObservable) SetName(v string) {
+	prev := o.value.Name
+	o.value.Name = v
+	for _, fn := range o.listeners {
+		fn("Name", prev, v)
+	}
+}
+func (o *TodoObservable) SetDone(v bool) {
+	prev := o.value.Done
+	o.value.Done = v
+	for _, fn := range o.listeners {
+		fn("Done", prev, v)
+	}
+}
+
+// OnChange registers fn, it is called after a field changed.
+func (o *TodoObservable) OnChange(fn func(field string, prev, next interface{})) {
+	o.listeners = append(o.listeners, fn)
+}
+
+// Get returns the observed value.
+func (o *TodoObservable) Get() Todo {
+	return o.value
+}
+
+type WatchedTodo struct {
+	TodoObservable
+}
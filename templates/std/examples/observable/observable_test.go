@@ -0,0 +1,24 @@
+package observable
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestWatchedTodo(t *testing.T) {
+	o := &WatchedTodo{}
+	changes := []string{}
+	o.OnChange(func(field string, prev, next interface{}) {
+		changes = append(changes, fmt.Sprintf("%v:%v->%v", field, prev, next))
+	})
+	o.SetName("tomate")
+	o.SetDone(true)
+
+	want := "[Name:->tomate Done:false->true]"
+	if got := fmt.Sprint(changes); got != want {
+		t.Errorf("unexpected changes wanted=%q, got=%q", want, got)
+	}
+	if got := o.Get(); got != (Todo{Name: "tomate", Done: true}) {
+		t.Errorf("unexpected value wanted=%v, got=%v", Todo{Name: "tomate", Done: true}, got)
+	}
+}
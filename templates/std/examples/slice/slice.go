@@ -0,0 +1,108 @@
+package slice
+
+import "sort"
+
+//gigo:use std "github.com/mh-cbon/gigo/templates/std"
+
+type Todo struct {
+	Name string
+	Done bool
+}
+
+// Slice is a list of a type.
+type TodoSlice struct {
+	items []Todo
+}
+
+// FindBy returns the first item with a field equal to value, for every field given in keys.
+func (s *TodoSlice) FindByName(value string) (Todo, bool) {
+	for _, item := range s.items {
+		if item.Name == value {
+			return item, true
+		}
+	}
+	return Todo{}, false
+}
+
+// Push appends items, it returns the new length.
+func (s *TodoSlice) Push(items ...Todo) int {
+	s.items = append(s.items, items...)
+	return len(s.items)
+}
+
+// Len returns the number of items.
+func (s *TodoSlice) Len() int {
+	return len(s.items)
+}
+
+// At returns the item at index i.
+func (s *TodoSlice) At(i int) Todo {
+	return s.items[i]
+}
+
+// Items returns a copy of the items.
+func (s *TodoSlice) Items() []Todo {
+	return append([]Todo{}, s.items...)
+}
+
+// Index returns the index of the first item equal to search, or -1.
+func (s *TodoSlice) Index(search Todo) int {
+	for i, item := range s.items {
+		if item == search {
+			return i
+		}
+	}
+	return -1
+}
+
+// RemoveAt removes the item at index i.
+func (s *TodoSlice) RemoveAt(i int) {
+	s.items = append(s.items[:i], s.items[i+1:]...)
+}
+
+// Remove removes the first item equal to item, it returns its index, or -1.
+func (s *TodoSlice) Remove(item Todo) int {
+	i := s.Index(item)
+	if i > -1 {
+		s.RemoveAt(i)
+	}
+	return i
+}
+
+// Filter returns a new slice of the items for which fn returns true.
+func (s *TodoSlice) Filter(fn func(Todo) bool) *TodoSlice {
+	ret := &TodoSlice{}
+	for _, item := range s.items {
+		if fn(item) {
+			ret.items = append(ret.items, item)
+		}
+	}
+	return ret
+}
+
+// Map returns a new slice of the items transformed by fn.
+func (s *TodoSlice) Map(fn func(Todo) Todo) *TodoSlice {
+	ret := &TodoSlice{}
+	for _, item := range s.items {
+		ret.items = append(ret.items, fn(item))
+	}
+	return ret
+}
+
+// Each calls fn for every item.
+func (s *TodoSlice) Each(fn func(int, Todo)) {
+	for i, item := range s.items {
+		fn(i, item)
+	}
+}
+
+// Sort sorts the items in place with less, the order of equal items is kept.
+func (s *TodoSlice) Sort(less func(a, b Todo) bool) {
+	sort.SliceStable(s.items, func(i, j int) bool {
+		return less(s.items[i], s.items[j])
+	})
+}
+
+type Todos struct {
+	TodoSlice
+}
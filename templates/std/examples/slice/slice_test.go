@@ -0,0 +1,48 @@
+package slice
+
+import "testing"
+
+func TestTodos(t *testing.T) {
+	s := &Todos{}
+	if got := s.Push(Todo{Name: "b"}, Todo{Name: "a", Done: true}, Todo{Name: "c"}); got != 3 {
+		t.Errorf("unexpected len wanted=%v, got=%v", 3, got)
+	}
+
+	todo, ok := s.FindByName("a")
+	if !ok || !todo.Done {
+		t.Errorf("unexpected todo wanted=%v, got=%v", Todo{Name: "a", Done: true}, todo)
+	}
+	if _, ok := s.FindByName("z"); ok {
+		t.Errorf("unexpected found wanted=%v, got=%v", false, ok)
+	}
+
+	done := s.Filter(func(t Todo) bool { return t.Done })
+	if done.Len() != 1 || done.At(0).Name != "a" {
+		t.Errorf("unexpected filter wanted=%v, got=%v", "[a]", done.Items())
+	}
+
+	upper := s.Map(func(t Todo) Todo {
+		t.Name += t.Name
+		return t
+	})
+	if got := upper.At(0).Name; got != "bb" {
+		t.Errorf("unexpected map wanted=%q, got=%q", "bb", got)
+	}
+
+	s.Sort(func(a, b Todo) bool { return a.Name < b.Name })
+	names := ""
+	s.Each(func(i int, t Todo) { names += t.Name })
+	if names != "abc" {
+		t.Errorf("unexpected sort wanted=%q, got=%q", "abc", names)
+	}
+
+	if got := s.Remove(Todo{Name: "b"}); got != 1 {
+		t.Errorf("unexpected index wanted=%v, got=%v", 1, got)
+	}
+	if got := s.Index(Todo{Name: "c"}); got != 1 {
+		t.Errorf("unexpected index wanted=%v, got=%v", 1, got)
+	}
+	if got := s.Remove(Todo{Name: "z"}); got != -1 {
+		t.Errorf("unexpected index wanted=%v, got=%v", -1, got)
+	}
+}
@@ -0,0 +1,51 @@
+package stringer
+
+import "fmt"
+
+//gigo:use std "github.com/mh-cbon/gigo/templates/std"
+
+type Todo struct {
+	Name string
+	Done bool
+}
+
+// Stringer prints a type as Name{Field:value ...}.
+type TodoStringer struct {
+	Todo
+}
+
+// String implements fmt.Stringer.
+func (s TodoStringer) String() string {
+	ret := "Todo" + "{"
+
+	ret += fmt.Sprintf("%v:%v", "Name", s.Name)
+
+	ret += " "
+	ret += fmt.Sprintf("%v:%v", "Done", s.Done)
+
+	return ret + "}"
+}
+
+type PrintedTodo struct {
+	TodoStringer
+}
+
+// Dumper dumps a type with one field per line.
+type TodoDumper struct {
+	Todo
+}
+
+// Dump returns the name of the type, then a line per field with its go syntax value.
+func (s TodoDumper) Dump() string {
+	ret := "Todo" + "\n"
+
+	ret += fmt.Sprintf("  %v: %#v\n", "Name", s.Name)
+
+	ret += fmt.Sprintf("  %v: %#v\n", "Done", s.Done)
+
+	return ret
+}
+
+type DumpedTodo struct {
+	TodoDumper
+}
@@ -0,0 +1,22 @@
+package stringer
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPrintedTodo(t *testing.T) {
+	todo := PrintedTodo{TodoStringer{Todo{Name: "tomate"}}}
+	want := "Todo{Name:tomate Done:false}"
+	if got := fmt.Sprint(todo); got != want {
+		t.Errorf("unexpected string wanted=%q, got=%q", want, got)
+	}
+}
+
+func TestDumpedTodo(t *testing.T) {
+	todo := DumpedTodo{TodoDumper{Todo{Name: "tomate", Done: true}}}
+	want := "Todo\n  Name: \"tomate\"\n  Done: true\n"
+	if got := todo.Dump(); got != want {
+		t.Errorf("unexpected dump wanted=%q, got=%q", want, got)
+	}
+}
@@ -0,0 +1,29 @@
+// +build gigo
+
+package main
+
+//gigo:use tpl "./tpls"
+
+type Todo struct {
+  Name string
+}
+
+type Todos implements<:tpl.Getter .Todo, .Todo | Slice> {}
+
+// GetName is promoted by TodoGetter and TodoSlice,
+// it is declared on Todos to resolve the ambiguity.
+func (t Todos) GetName() string {
+  return t.TodoGetter.GetName()
+}
+
+template <:.Name>Slice struct {
+  items []<:.Name>
+}
+
+func (s <:.Name>Slice) Len() int {
+  return len(s.items)
+}
+
+func (s <:.Name>Slice) GetName() string {
+  return <:quote .Name>
+}
@@ -0,0 +1,52 @@
+// +build gigo
+
+package main
+
+type Todo struct {
+  Name string
+}
+
+type Todos implements<:Slice .Todo> {}
+
+func (t *Todos) Reset() {
+  t.items = nil
+}
+
+func (t *Todos) count() int {
+  return len(t.items)
+}
+
+type TodoSliceAPI implements<:Interface (Slice .Todo) true> {}
+
+type TodosResetter implements<:.Todos | Interface false "Reset" "c*"> {}
+
+type TodosMock implements<:Mock (Interface .Todos true)> {}
+
+template <:.Name>Slice struct {
+  items []<:.Name>
+}
+
+func (s *<:.Name>Slice) Push(x ...<:.Name>) {
+  s.items = append(s.items, x...)
+}
+
+func (s *<:.Name>Slice) Get(i int) (<:.Name>, bool) {
+  if i < len(s.items) {
+    return s.items[i], true
+  }
+  var x <:.Name>
+  return x, false
+}
+
+func (s *<:.Name>Slice) len() int {
+  return len(s.items)
+}
+
+template <:.Name>Mock struct {
+  <:range .Methods><:.Name>Func func(<:.ParamsDecl>) <:.ResultsDecl>
+  <:end>
+}
+
+<:range $m := .Methods> func (m *<:$.Name>Mock) <:$m.Name>(<:$m.ParamsDecl>) <:$m.ResultsDecl> {
+  <:if $m.HasResults>return <:end>m.<:$m.Name>Func(<:$m.CallArgs>)
+}
@@ -0,0 +1,37 @@
+// +build gigo
+
+package main
+
+type Todo struct {
+  Name string
+}
+
+type Todos implements<:Slice .Todo> {}
+
+template <:.Name>Slice struct {
+  items []<:.Name>
+}
+
+func (s *<:.Name>Slice) Push(x <:.Name>) {
+  s.items = append(s.items, x)
+}
+
+func (s *<:.Name>Slice) Iter() *<:.Name>Iter {
+  return &<:.Name>Iter{s: s, i: -1}
+}
+
+template(Slice) const <:.Name>SliceMax = 100
+
+template(Slice) func New<:.Name>Slice(items ...<:.Name>) *<:.Name>Slice {
+  return &<:.Name>Slice{items: items}
+}
+
+template(Slice) type <:.Name>Iter struct {
+  s *<:.Name>Slice
+  i int
+}
+
+template(Slice) func (it *<:.Name>Iter) Next() bool {
+  it.i++
+  return it.i < len(it.s.items)
+}
@@ -0,0 +1,81 @@
+// +build gigo
+
+package main
+
+import "sync"
+
+type Todo struct {
+  Name string
+}
+
+type Task struct {
+  Title string
+}
+
+// Todos and Tasks share the generic type Slice.
+type Todos implements<:Slice .Todo> {}
+
+type Tasks implements<:.Task | Slice | Mutexed> {}
+
+type TodoNames implements<:Named .Todo> {}
+
+// TodoFinder can not be written with a type parameter, it ranges over the fields.
+type TodoFinder implements<:Index .Todo> {}
+
+template <:.Name>Slice struct {
+  items []<:.Name>
+}
+
+func (s *<:.Name>Slice) Push(x <:.Name>) {
+  s.items = append(s.items, x)
+}
+
+func (s *<:.Name>Slice) At(i int) <:.Name> {
+  return s.items[i]
+}
+
+func (s *<:.Name>Slice) Len() int {
+  return len(s.items)
+}
+
+// Index compares the items, the type parameter is comparable.
+func (s *<:.Name>Slice) Index(x <:.Name>) int {
+  for i, item := range s.items {
+    if item == x {
+      return i
+    }
+  }
+  return -1
+}
+
+// Named reads the field Name of the items, it is expanded.
+template <:.Name>Named struct {
+  items []<:.Name>
+}
+
+func (s *<:.Name>Named) Names() []string {
+  ret := []string{}
+  for _, item := range s.items {
+    ret = append(ret, item.Name)
+  }
+  return ret
+}
+
+template <:.Name>Mutexed struct {
+  lock sync.Mutex
+  embed <:.Name>
+}
+
+template <:.Name>Index struct {
+  items []<:.Name>
+}
+
+<:range $f := .Props> func (s *<:$.Name>Index) By<:$f.Name>(v <:$f.Type>) []<:$.Name> {
+  ret := []<:$.Name>{}
+  for _, i := range s.items {
+    if i.<:$f.Name> == v {
+      ret = append(ret, i)
+    }
+  }
+  return ret
+}
@@ -0,0 +1,19 @@
+// +build gigo
+
+package main
+
+import (
+  "github.com/mh-cbon/gigo/testdata/models"
+)
+
+type Todos implements<:Slice models.Todo> {}
+
+template <:.Name>Slice struct {
+  items []<:.GetQualifiedName>
+}
+
+<:range $m := .Methods> func (s *<:$.Name>Slice) <:$m.Name>All(<:$m.ParamsDecl>) {
+  for i := range s.items {
+    s.items[i].<:$m.Name>(<:$m.CallArgs>)
+  }
+}
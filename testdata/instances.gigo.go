@@ -0,0 +1,35 @@
+// +build gigo
+
+package main
+
+import "sync"
+
+type Todo struct {
+  Name string
+}
+
+// Todos and Tasks share the same TodoSlice.
+type Todos implements<:Mutexed (Slice .Todo "Name")> {}
+
+type Tasks implements<:.Todo | Slice "Name"> {}
+
+type TodoSet implements<:Mutexed (Slice .Todo "Name")> {}
+
+template <:.Name>Slice struct {
+  items []<:.Name>
+}
+
+<:range $i, $a := .Args> func (s *<:$.Name>Slice) By<:$a>(v <:$.ArgType $a>) []<:$.Name> {
+  ret := []<:$.Name>{}
+  for _, i := range s.items {
+    if i.<:$a> == v {
+      ret = append(ret, i)
+    }
+  }
+  return ret
+}
+
+template <:.Name>Mutexed struct {
+  lock sync.Mutex
+  embed <:.Name>
+}
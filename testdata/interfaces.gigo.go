@@ -0,0 +1,36 @@
+// +build gigo
+
+package main
+
+import "log"
+
+type Store interface {
+  Get(id int) (string, error)
+  Put(id int, v string) error
+  Len() int
+}
+
+type FakeStore implements<:Mock .Store> {}
+
+type AuditedStore implements<:Logged .Store> {}
+
+// Mock implements an interface with a func per method.
+template <:.Name>Mock struct {
+  <:range .Methods><:.Name>Func func(<:.ParamsDecl>) <:.ResultsDecl>
+  <:end>
+}
+
+<:range $m := .Methods> func (m *<:$.Name>Mock) <:$m.Name>(<:$m.ParamsDecl>) <:$m.ResultsDecl> {
+  <:if $m.HasResults>return <:end>m.<:$m.Name>Func(<:$m.CallArgs>)
+}
+
+// Logged logs the calls to an interface.
+template Logged<:.Name> struct {
+  embed <:.GetQualifiedName>
+}
+
+<:range $m := .Methods> func (l *Logged<:$.Name>) <:$m.Name>(<:$m.ParamsDecl>) <:$m.NamedResultsDecl> {
+  log.Println(<:quote $m.Name>)
+  <:$m.AssignResults>l.embed.<:$m.Name>(<:$m.CallArgs>)
+  return <:$m.ReturnList>
+}
@@ -0,0 +1,24 @@
+// +build gigo
+
+package main
+
+//gigo:use tpl "./tpls"
+
+type Todo struct {
+  Name string
+}
+
+type Item struct {
+  ID int
+}
+
+type TodoAccess implements<:tpl.Getter .Todo> {}
+
+type ItemRef implements<:tpl.Ptr .Item> {}
+
+// the local template wins over the library.
+type TodoRef implements<:Ptr .Todo> {}
+
+template <:.Name>Ptr struct {
+  local *<:.Name>
+}
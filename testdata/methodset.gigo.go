@@ -0,0 +1,43 @@
+// +build gigo
+
+package main
+
+type Todo struct {
+  Name string
+}
+
+type Todos implements<:Slice .Todo> {}
+
+func (t Todos) First() Todo {
+  return t.items[0]
+}
+
+type SafeTodos implements<:Counted .Todos> {}
+
+template <:.Name>Slice struct {
+  items []<:.Name>
+}
+
+func (s *<:.Name>Slice) Push(x <:.Name>) {
+  s.items = append(s.items, x)
+}
+
+func (s <:.Name>Slice) Len() int {
+  return len(s.items)
+}
+
+// Counted counts the calls to the method set of *T,
+// the methods of T are listed in ValueMethods.
+template Counted<:.Name> struct {
+  embed <:.Name>
+  calls map[string]int
+}
+
+func (c *Counted<:.Name>) ValueMethods() []string {
+  return []string{<:range $i, $m := .MethodSet><:if $i>, <:end><:quote $m.Name><:end>}
+}
+
+<:range $m := .PointerMethodSet> func (c *Counted<:$.Name>) <:$m.Name>(<:$m.ParamsDecl>) <:$m.ResultsDecl> {
+  c.calls[<:quote $m.Name>]++
+  <:if $m.HasResults>return <:end>c.embed.<:$m.Name>(<:$m.CallArgs>)
+}
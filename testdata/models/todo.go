@@ -0,0 +1,14 @@
+package models
+
+type (
+	// Todo is a task.
+	Todo struct {
+		Name string
+		Done bool
+	}
+)
+
+// Rename the task.
+func (t *Todo) Rename(name string) {
+	t.Name = name
+}
@@ -0,0 +1,31 @@
+// +build gigo
+
+package main
+
+type Todo struct {
+  Name string
+}
+
+type User struct {
+  ID int
+}
+
+type TodoByUser implements<:Map .User .Todo> {}
+
+type TodoByName implements<:Map "string" .Todo> {}
+
+type Names implements<:Pair .Todo "string"> {}
+
+template <:.T1.Title><:.T2.Title>Map struct {
+  items map[<:.T1>]<:.T2>
+}
+
+func (m <:.T1.Title><:.T2.Title>Map) Get(k <:.T1>) (<:.T2>, bool) {
+  v, ok := m.items[k]
+  return v, ok
+}
+
+template <:.Name><:.Params.second.Title>Pair(second Type) struct {
+  A <:.T1>
+  B <:.Params.second>
+}
@@ -0,0 +1,33 @@
+// +build gigo
+
+package main
+
+import "sync"
+
+type Todo struct {
+  Name string
+  Done bool
+}
+
+func (t *Todo) Rename(name string) {
+  t.Name = name
+}
+
+func (t Todo) IsDone() bool {
+  return t.Done
+}
+
+type SafeTodo implements<:Mutexed .Todo> {}
+
+// a template to mutex .
+template Mutexed<:.Name> struct {
+  lock sync.Mutex
+  embed <:.Name>
+}
+
+<:range $m := .Methods> func (m *Mutexed<:$.Name>) <:$m.Name>(<:$m.ParamsDecl>) <:$m.NamedResultsDecl> {
+  m.lock.Lock()
+  defer m.lock.Unlock()
+  <:$m.AssignResults>m.embed.<:$m.Name>(<:$m.CallArgs>)
+  return <:$m.ReturnList>
+}
@@ -0,0 +1,26 @@
+// +build gigo
+
+package main
+
+type Todo struct {
+  Name string
+  ID int
+}
+
+type Todos implements<:Index .Todo "Name" "ID" true> {}
+
+// Index finds the items by the fields given in keys.
+template <:.Name>Index(keys ...field, unique bool) struct {
+  items []<:.Name>
+}
+
+<:range $k := .Params.keys> func (s *<:$.Name>Index) By<:$k>(v <:$.ArgType $k>) []<:$.Name> {
+  ret := []<:$.Name>{}
+  for _, i := range s.items {
+    if i.<:$k> == v {
+      ret = append(ret, i)<:if $.Params.unique>
+      break<:end>
+    }
+  }
+  return ret
+}
@@ -0,0 +1,35 @@
+// +build gigo
+
+package main
+
+import "sync"
+
+//gigo:use tpl "./tpls"
+
+type Todo struct {
+  Name string
+}
+
+// Todos is a Mutexed of a Slice of Todo.
+type Todos implements<:.Todo | Slice "Name" | Mutexed> {}
+
+type TodoAccess implements<:.Todo | tpl.Getter> {}
+
+template <:.Name>Slice struct {
+  items []<:.Name>
+}
+
+<:range $i, $a := .Args> func (s *<:$.Name>Slice) By<:$a>(v <:$.ArgType $a>) []<:$.Name> {
+  ret := []<:$.Name>{}
+  for _, i := range s.items {
+    if i.<:$a> == v {
+      ret = append(ret, i)
+    }
+  }
+  return ret
+}
+
+template <:.Name>Mutexed struct {
+  lock sync.Mutex
+  embed <:.Name>
+}
@@ -0,0 +1,36 @@
+// +build gigo
+
+package main
+
+import (
+  "github.com/mh-cbon/gigo/testdata/store"
+)
+
+// the types of the store package are qualified, store.Item.
+type FakeStore implements<:Mock store.Store> {}
+
+type RepoAPI implements<:Interface store.Repo true> {}
+
+type Repos implements<:Slice store.Repo> {}
+
+// Mock implements an interface with a func per method.
+template <:.Name>Mock struct {
+  <:range .Methods><:.Name>Func func(<:.ParamsDecl>) <:.ResultsDecl>
+  <:end>
+}
+
+<:range $m := .Methods> func (m *<:$.Name>Mock) <:$m.Name>(<:$m.ParamsDecl>) <:$m.ResultsDecl> {
+  <:if $m.HasResults>return <:end>m.<:$m.Name>Func(<:$m.CallArgs>)
+}
+
+template <:.Name>Slice struct {
+  items []*<:.GetQualifiedName>
+}
+
+<:range $m := .Methods> func (s *<:$.Name>Slice) <:$m.Name>(<:$m.ParamsDecl>) []<:$m.ResultsDecl> {
+  ret := []<:$m.ResultsDecl>{}
+  for _, i := range s.items {
+    ret = append(ret, i.<:$m.Name>(<:$m.CallArgs>))
+  }
+  return ret
+}
@@ -0,0 +1,35 @@
+package store
+
+// ID identifies an item.
+type ID int
+
+// Item is a stored value.
+type Item struct {
+	ID   ID
+	Name string
+}
+
+// Store saves the items.
+type Store interface {
+	Get(id ID) (Item, error)
+	Put(item *Item) error
+}
+
+// Repo is an in memory Store.
+type Repo struct {
+	Items map[ID]Item
+}
+
+// Find returns the item of id.
+func (r *Repo) Find(id ID) Item {
+	return r.Items[id]
+}
+
+// All returns every item.
+func (r *Repo) All() []Item {
+	ret := []Item{}
+	for _, i := range r.Items {
+		ret = append(ret, i)
+	}
+	return ret
+}